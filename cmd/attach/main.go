@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-phings/terminal-ui/session"
+)
+
+// getSize gets terminal size by calling stty command
+func getSize() (int, int, error) {
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return 0, 0, err
+	}
+	nums := strings.Fields(string(out))
+	if len(nums) != 2 {
+		return 0, 0, fmt.Errorf("invalid stty output: %s", out)
+	}
+	h, err := strconv.Atoi(nums[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	w, err := strconv.Atoi(nums[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return w, h, nil
+}
+
+// stty calls stty command with arguments on the terminal
+func stty(args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
+
+// resize sends terminal size to the server
+func resize(c *session.Client) {
+	w, h, err := getSize()
+	if err == nil {
+		c.Resize(w, h)
+	}
+}
+
+func main() {
+	path := flag.String("s", filepath.Join(os.TempDir(), "terminal-ui.sock"), "path to the session socket")
+	readOnly := flag.Bool("r", false, "attach read-only")
+	flag.Parse()
+
+	c, err := session.Attach(*path, *readOnly)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	err = stty("cbreak", "min", "1", "-echo")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	resize(c)
+
+	// ctrl+c detaches
+	signals := make(chan os.Signal, 1)
+	notifySignals(signals)
+	done := make(chan string, 2)
+	go func() {
+		for s := range signals {
+			if isResize(s) {
+				resize(c)
+				continue
+			}
+			done <- "detached"
+			return
+		}
+	}()
+	go func() {
+		io.Copy(os.Stdout, c)
+		done <- "session ended"
+	}()
+	if !*readOnly {
+		go io.Copy(c, os.Stdin)
+	}
+
+	msg := <-done
+	c.Close()
+	fmt.Print("\u001b[2J\u001b[1000A\u001b[1000D")
+	stty("sane")
+	fmt.Println("[" + msg + "]")
+}
@@ -0,0 +1,19 @@
+//go:build !unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+)
+
+// notifySignals relays interrupt to the channel. There is no signal on
+// window resize on this platform.
+func notifySignals(ch chan os.Signal) {
+	signal.Notify(ch, os.Interrupt)
+}
+
+// isResize always returns false
+func isResize(s os.Signal) bool {
+	return false
+}
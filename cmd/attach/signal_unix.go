@@ -0,0 +1,20 @@
+//go:build unix
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifySignals relays window resize and signals that end the program to
+// the channel
+func notifySignals(ch chan os.Signal) {
+	signal.Notify(ch, syscall.SIGWINCH, syscall.SIGINT, syscall.SIGTERM)
+}
+
+// isResize returns true when signal is sent after terminal window resize
+func isResize(s os.Signal) bool {
+	return s == syscall.SIGWINCH
+}
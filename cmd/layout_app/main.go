@@ -0,0 +1,48 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tui "github.com/go-phings/terminal-ui"
+)
+
+//go:embed layout.json
+var layout string
+
+func main() {
+	myTUI := tui.NewTUI()
+
+	myTUI.RegisterWidget("clock", func(p *tui.TUIPane) {
+		w := tui.NewTUIWidgetSample()
+		w.InitPane(p)
+		p.SetOnDraw(w.Run)
+		p.SetOnIterate(w.Run)
+	})
+	myTUI.RegisterWidget("log", func(p *tui.TUIPane) {
+		w := tui.NewTUIWidgetLog(100)
+		w.InitPane(p)
+		p.SetOnDraw(w.Run)
+		// lines can have colors
+		w.Add("\u001b[32mready\u001b[0m")
+	})
+
+	err := myTUI.LoadLayout(strings.NewReader(layout))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	myTUI.GetPaneByName("tabs").BindTabKeys()
+
+	// Try: go run ./cmd/tuictl -s /tmp/layout_app.sock log footer hello
+	err = myTUI.ListenControl(filepath.Join(os.TempDir(), "layout_app.sock"))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	myTUI.Run(os.Stdout, os.Stderr)
+}
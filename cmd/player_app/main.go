@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	tui "github.com/go-phings/terminal-ui"
+)
+
+// Usage:
+//
+//	player_app -record demo.cast   runs demo and records it (with input)
+//	player_app -replay demo.cast   runs demo and replays recorded input
+//	player_app demo.cast           plays the recording in a pane
+func main() {
+	record := flag.String("record", "", "record demo to file")
+	replay := flag.String("replay", "", "run demo replaying input from file")
+	speed := flag.Float64("speed", 1, "playback speed")
+	flag.Parse()
+
+	var code int
+	switch {
+	case *record != "":
+		code = runRecord(*record)
+	case *replay != "":
+		code = runReplay(*replay, *speed)
+	case flag.NArg() == 1:
+		code = runPlayer(flag.Arg(0), *speed)
+	default:
+		flag.Usage()
+		code = 2
+	}
+	os.Exit(code)
+}
+
+// newDemo returns interface with a counter that is increased with space
+func newDemo() *tui.TUI {
+	myTUI := tui.NewTUI()
+	counter := 0
+	p1, p2 := myTUI.GetPane().SplitHorizontally(-5, tui.UNIT_CHAR)
+	p1.SetStyle(tui.NewTUIPaneStyleFrame())
+	p2.SetStyle(tui.NewTUIPaneStyleFrame())
+	p1.SetOnDraw(func(p *tui.TUIPane) int {
+		p.Write(0, 0, "counter: "+strconv.Itoa(counter), false)
+		p.Write(0, 2, "press space to increase the counter, 'q' to quit", false)
+		return tui.RESULT_OK
+	})
+
+	w := tui.NewTUIWidgetSample()
+	w.InitPane(p2)
+	p2.SetOnDraw(w.Run)
+	p2.SetOnIterate(w.Run)
+
+	myTUI.SetOnKeyPress(func(t *tui.TUI, b []byte) {
+		switch string(b) {
+		case tui.KEY_SPACE:
+			counter++
+			p1.Invalidate()
+		case "q":
+			t.Stop(0)
+		}
+	})
+	return myTUI
+}
+
+func runRecord(path string) int {
+	f, err := os.Create(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	defer f.Close()
+
+	myTUI := newDemo()
+	myTUI.SetTTY(os.Stdin)
+	err = myTUI.StartRecording(f, true)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	code := myTUI.Run(os.Stdout, os.Stderr)
+	err = myTUI.StopRecording()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+	return code
+}
+
+func runReplay(path string, speed float64) int {
+	c, err := readCast(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	myTUI := newDemo()
+	// timers start when interface is running
+	myTUI.GetPane().AfterFunc(100, func(p *tui.TUIPane) {
+		go myTUI.Replay(c, speed)
+	})
+	return myTUI.Run(os.Stdout, os.Stderr)
+}
+
+func runPlayer(path string, speed float64) int {
+	c, err := readCast(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		return 1
+	}
+
+	myTUI := tui.NewTUI()
+	p := myTUI.GetPane()
+	p.SetStyle(tui.NewTUIPaneStyleFrame())
+	w := tui.NewTUIWidgetPlayer(c)
+	w.SetSpeed(speed)
+	w.InitPane(p)
+	p.SetOnDraw(w.Run)
+
+	myTUI.SetOnKeyPress(func(t *tui.TUI, b []byte) {
+		if string(b) == "q" {
+			t.Stop(0)
+			return
+		}
+		w.KeyPress(p, b)
+	})
+	return myTUI.Run(os.Stdout, os.Stderr)
+}
+
+func readCast(path string) (*tui.TUIAsciicast, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return tui.ReadAsciicast(f)
+}
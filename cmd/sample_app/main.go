@@ -63,5 +63,8 @@ func main() {
 	p31.SetOnIterate(getOnTUIPaneDraw(p31))
 	p32.SetOnIterate(getOnTUIPaneDraw(p32))
 
+	// Save screenshot of the interface when 's' is pressed
+	myTUI.BindCaptureKey("s", "screenshot-{time}.svg", nil)
+
 	myTUI.Run(os.Stdout, os.Stderr)
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	tui "github.com/go-phings/terminal-ui"
+	"github.com/go-phings/terminal-ui/session"
+)
+
+func main() {
+	myTUI := tui.NewTUI()
+
+	// Counter keeps its value when clients detach and attach again.
+	counter := 0
+	p1, p2 := myTUI.GetPane().SplitHorizontally(-5, tui.UNIT_CHAR)
+	p1.SetStyle(tui.NewTUIPaneStyleFrame())
+	p2.SetStyle(tui.NewTUIPaneStyleFrame())
+	p1.SetOnDraw(func(p *tui.TUIPane) int {
+		p.Write(0, 0, "counter: "+strconv.Itoa(counter), false)
+		p.Write(0, 2, "press space to increase the counter, 'q' to quit", false)
+		p.Write(0, 3, "press ctrl+c to detach", false)
+		return tui.RESULT_OK
+	})
+
+	w := tui.NewTUIWidgetSample()
+	w.InitPane(p2)
+	p2.SetOnDraw(w.Run)
+	p2.SetOnIterate(w.Run)
+
+	myTUI.SetOnKeyPress(func(t *tui.TUI, b []byte) {
+		switch string(b) {
+		case tui.KEY_SPACE:
+			counter++
+			p1.Invalidate()
+		case "q":
+			t.Stop(0)
+		}
+	})
+
+	path := filepath.Join(os.TempDir(), "terminal-ui.sock")
+	fmt.Println("attach with: go run ./cmd/attach -s " + path)
+	_, err := session.NewServer(myTUI, path).Run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"os"
+
+	tui "github.com/go-phings/terminal-ui"
+	tuissh "github.com/go-phings/terminal-ui/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// newTUI creates interface for a connected client: a pane with user details
+// and a clock. Pressing 'q' disconnects.
+func newTUI(s *tuissh.Session) *tui.TUI {
+	myTUI := tui.NewTUI()
+
+	p1, p2 := myTUI.GetPane().SplitHorizontally(-5, tui.UNIT_CHAR)
+	p1.SetStyle(tui.NewTUIPaneStyleFrame())
+	p2.SetStyle(tui.NewTUIPaneStyleFrame())
+
+	p1.SetOnDraw(func(p *tui.TUIPane) int {
+		p.Write(0, 0, "user: "+s.GetUser(), false)
+		p.Write(0, 1, "from: "+s.GetRemoteAddr().String(), false)
+		p.Write(0, 2, "term: "+s.GetTerm(), false)
+		p.Write(0, 3, fmt.Sprintf("size: %dx%d", myTUI.GetWidth(), myTUI.GetHeight()), false)
+		p.Write(0, 5, "press 'q' to quit", false)
+		return tui.RESULT_OK
+	})
+
+	w := tui.NewTUIWidgetSample()
+	w.InitPane(p2)
+	p2.SetOnDraw(w.Run)
+	p2.SetOnIterate(w.Run)
+
+	myTUI.SetOnKeyPress(func(t *tui.TUI, b []byte) {
+		if string(b) == "q" {
+			t.Stop(0)
+		}
+	})
+	return myTUI
+}
+
+func main() {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+
+	// Anyone can connect, so the server listens on localhost only.
+	config := &gossh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	addr := "127.0.0.1:2222"
+	fmt.Println("listening on " + addr + ", connect with: ssh -p 2222 localhost")
+	err = tuissh.NewServer(config, newTUI).ListenAndServe(addr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"os"
+
+	tui "github.com/go-phings/terminal-ui"
+)
+
+func main() {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = "/bin/sh"
+	}
+
+	myTUI := tui.NewTUI()
+	top, status := myTUI.GetPane().SplitHorizontally(-1, tui.UNIT_CHAR)
+	left, right := top.SplitVertically(50, tui.UNIT_PERCENT)
+
+	status.SetOnDraw(func(p *tui.TUIPane) int {
+		p.Write(0, 0, "alt+left/alt+right: switch pane, ctrl+q: quit", false)
+		return tui.RESULT_OK
+	})
+
+	panes := []*tui.TUIPane{left, right}
+	for _, p := range panes {
+		p.SetStyle(tui.NewTUIPaneStyleFrame())
+		w := tui.NewTUIWidgetTerminal(shell)
+		w.InitPane(p)
+		p.SetOnDraw(w.Run)
+		w.SetOnExit(func(p *tui.TUIPane, err error) {
+			// quit when all the shells have exited
+			for _, c := range panes {
+				if c.GetTerminal().IsRunning() {
+					return
+				}
+			}
+			myTUI.Stop(0)
+		})
+	}
+	myTUI.SetFocus(left)
+
+	focus := func(i int) func(*tui.TUI) {
+		return func(t *tui.TUI) {
+			t.SetFocus(panes[i])
+			left.Invalidate()
+			right.Invalidate()
+		}
+	}
+	myTUI.BindKey(tui.KEY_ALT_LEFT, focus(0))
+	myTUI.BindKey(tui.KEY_ALT_RIGHT, focus(1))
+	myTUI.BindKey("\u0011", func(t *tui.TUI) {
+		t.Stop(0)
+	})
+
+	os.Exit(myTUI.Run(os.Stdout, os.Stderr))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	tui "github.com/go-phings/terminal-ui"
+)
+
+func main() {
+	dir := "."
+	if len(os.Args) > 1 {
+		dir = os.Args[1]
+	}
+
+	myTUI := tui.NewTUI()
+	top, status := myTUI.GetPane().SplitHorizontally(-1, tui.UNIT_CHAR)
+	top.SetStyle(tui.NewTUIPaneStyleFrame())
+
+	// directories are read only when they are expanded
+	tree := tui.NewTUIWidgetTree(tui.NewTUIWidgetTreeNode(dir, dir))
+	tree.SetShowRoot(true)
+	tree.SetOnLoad(func(n *tui.TUIWidgetTreeNode) []*tui.TUIWidgetTreeNode {
+		path := n.GetData().(string)
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].IsDir() && !entries[j].IsDir()
+		})
+		var nodes []*tui.TUIWidgetTreeNode
+		for _, e := range entries {
+			c := tui.NewTUIWidgetTreeNode(e.Name(), filepath.Join(path, e.Name()))
+			c.SetLeaf(!e.IsDir())
+			nodes = append(nodes, c)
+		}
+		return nodes
+	})
+	tree.InitPane(top)
+	top.SetOnDraw(tree.Run)
+
+	selected := ""
+	tree.SetOnSelect(func(n *tui.TUIWidgetTreeNode) {
+		selected = n.GetData().(string)
+		status.Invalidate()
+	})
+	status.SetOnDraw(func(p *tui.TUIPane) int {
+		s := "arrows: move, space: expand, enter: select, type to search, ctrl+q: quit"
+		if selected != "" {
+			s = selected
+		}
+		p.Write(0, 0, tui.FitANSI(s, p.GetContentWidth()), false)
+		return tui.RESULT_OK
+	})
+
+	myTUI.BindKey("\u0011", func(t *tui.TUI) {
+		t.Stop(0)
+	})
+	myTUI.SetOnKeyPress(func(t *tui.TUI, b []byte) {
+		tree.KeyPress(top, b)
+	})
+
+	os.Exit(myTUI.Run(os.Stdout, os.Stderr))
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+)
+
+// send writes command to the control socket and prints out the response.
+// It returns false when command failed.
+func send(c net.Conn, r *bufio.Reader, cmd string) (bool, error) {
+	_, err := io.WriteString(c, cmd+"\n")
+	if err != nil {
+		return false, err
+	}
+	l, err := r.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	l = strings.TrimSuffix(l, "\n")
+	if strings.HasPrefix(l, "error") {
+		fmt.Fprintln(os.Stderr, strings.TrimSpace(strings.TrimPrefix(l, "error")))
+		return false, nil
+	}
+	if s := strings.TrimSpace(strings.TrimPrefix(l, "ok")); s != "" {
+		fmt.Println(s)
+	}
+	return true, nil
+}
+
+func main() {
+	path := flag.String("s", "", "path to the control socket")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: tuictl -s SOCKET [COMMAND [ARGS...]]")
+		fmt.Fprintln(os.Stderr, "when no command is given, commands are read from standard input, one per line")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	c, err := net.Dial("unix", *path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+	defer c.Close()
+	r := bufio.NewReader(c)
+
+	var cmds []string
+	if flag.NArg() > 0 {
+		cmds = []string{strings.Join(flag.Args(), " ")}
+	} else {
+		s := bufio.NewScanner(os.Stdin)
+		for s.Scan() {
+			if strings.TrimSpace(s.Text()) != "" {
+				cmds = append(cmds, s.Text())
+			}
+		}
+	}
+
+	failed := false
+	for _, cmd := range cmds {
+		ok, err := send(c, r, cmd)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err.Error())
+			os.Exit(1)
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	tui "github.com/go-phings/terminal-ui"
+	"github.com/go-phings/terminal-ui/web"
+)
+
+// newTUI creates interface for a connected browser: a pane with connection
+// details and a clock. Pressing 'q' disconnects.
+func newTUI(r *http.Request) *tui.TUI {
+	myTUI := tui.NewTUI()
+	myTUI.SetMouse(true)
+
+	p1, p2 := myTUI.GetPane().SplitHorizontally(-5, tui.UNIT_CHAR)
+	p1.SetStyle(tui.NewTUIPaneStyleFrame())
+	p2.SetStyle(tui.NewTUIPaneStyleFrame())
+
+	p1.SetOnDraw(func(p *tui.TUIPane) int {
+		p.Write(0, 0, "from: "+r.RemoteAddr, false)
+		p.Write(0, 1, fmt.Sprintf("size: %dx%d", myTUI.GetWidth(), myTUI.GetHeight()), false)
+		p.Write(0, 3, "press 'q' to quit", false)
+		return tui.RESULT_OK
+	})
+
+	w := tui.NewTUIWidgetSample()
+	w.InitPane(p2)
+	p2.SetOnDraw(w.Run)
+	p2.SetOnIterate(w.Run)
+
+	myTUI.SetOnKeyPress(func(t *tui.TUI, b []byte) {
+		if string(b) == "q" {
+			t.Stop(0)
+		}
+	})
+	return myTUI
+}
+
+func main() {
+	// Change the address to make it available on the local network.
+	addr := "127.0.0.1:8080"
+	fmt.Println("open http://" + addr + " in the browser")
+	err := http.ListenAndServe(addr, web.NewHandler(newTUI))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+		os.Exit(1)
+	}
+}
@@ -2,4 +2,11 @@ module github.com/go-phings/terminal-ui
 
 go 1.23.4
 
-require github.com/mikolajgs/terminal-ui v0.2.0
+require (
+	github.com/creack/pty v1.1.24
+	github.com/gorilla/websocket v1.5.3
+	golang.org/x/crypto v0.41.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.35.0 // indirect
@@ -1,69 +1,343 @@
 package terminalui
 
 import (
-	"fmt"
-	"log"
+	"io"
 	"os"
 	"os/exec"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// initTTY initialises terminal window
-func (t *TUI) initTTY() {
-	cmd1 := exec.Command("stty", "cbreak", "min", "1")
-	cmd1.Stdin = os.Stdin
-	err := cmd1.Run()
-	if err != nil {
-		log.Fatal(err)
-	}
+// sizeCheckInterval is the longest time between checks of tty size. Size is
+// checked straight away when terminal window is resized (see watchResize).
+const sizeCheckInterval = time.Second
 
-	cmd2 := exec.Command("stty", "-echo")
-	cmd2.Stdin = os.Stdin
-	err = cmd2.Run()
-	if err != nil {
-		log.Fatal(err)
+// initTTY switches terminal to cbreak and no echo mode. Nothing is done when
+// there is no tty.
+func (t *TUI) initTTY() error {
+	if t.tty == nil {
+		return nil
 	}
+	// one command so terminal is not left half set up when it fails
+	cmd := exec.Command("stty", "cbreak", "min", "1", "-echo")
+	cmd.Stdin = t.tty
+	return cmd.Run()
 }
 
 // clear clears terminal window
 func (t *TUI) clear() {
-	fmt.Fprintf(t.stdout, "\u001b[2J\u001b[1000A\u001b[1000D")
+	t.print("\u001b[2J\u001b[1000A\u001b[1000D")
+}
+
+// print writes string to the output so that writes from different
+// goroutines never get mixed up
+func (t *TUI) print(s string) {
+	if t.out == nil {
+		return
+	}
+	t.stdoutMu.Lock()
+	io.WriteString(t.out, s)
+	if t.screen != nil {
+		t.screen.Write([]byte(s))
+	}
+	t.recordLocked(ASCIICAST_OUTPUT, s)
+	t.stdoutMu.Unlock()
 }
 
-// startMainLoop initialises program's main loop, controls the terminal size, ensures panes are correctly
-// drawn and calls methods attached to their onIterate property
+// startMainLoop initialises program's main loop which runs on the UI
+// goroutine. It controls the terminal size, ensures panes are correctly
+// drawn, calls methods attached to their onIterate property, and calls
+// functions posted from other goroutines (including keyboard input).
 func (t *TUI) startMainLoop() {
+	defer t.restoreOnPanic()
+	next := time.Now()
+	tick := time.NewTimer(0)
 	for {
-		sizeChanged := t.refreshSize()
-		if sizeChanged {
-			t.clear()
-			if t.onDraw != nil {
-				t.onDraw(t)
+		select {
+		case <-tick.C:
+			// size of tty is checked with stty, so not with every iteration
+			// but only as a fallback when resize signal was missed
+			if t.tty == nil || time.Since(t.sizeChecked) >= sizeCheckInterval {
+				if t.refreshSize() {
+					t.draw()
+				}
 			}
-			t.pane.Draw()
+			now := time.Now()
+			t.iterate(now)
+			next = now.Add(t.getIterateDelay(now))
+			tick.Reset(next.Sub(now))
+		case <-t.wake:
+		}
+		t.runEvents()
+		if t.stopping {
+			t.eventsMu.Lock()
+			t.stopped = true
+			t.eventsMu.Unlock()
+			close(t.done)
+			return
+		}
+		// panes could have been shown or got shorter iterate interval
+		if now := time.Now(); now.Add(t.getIterateDelay(now)).Before(next) {
+			next = now.Add(t.getIterateDelay(now))
+			tick.Reset(next.Sub(now))
+		}
+	}
+}
+
+// isRunning returns true when main loop is running
+func (t *TUI) isRunning() bool {
+	t.eventsMu.Lock()
+	defer t.eventsMu.Unlock()
+	return t.running
+}
+
+// setRunning sets whether main loop is running
+func (t *TUI) setRunning(b bool) {
+	t.eventsMu.Lock()
+	t.running = b
+	t.stopped = false
+	t.eventsMu.Unlock()
+}
+
+// wakeUp makes main loop call posted functions without waiting for the
+// next iteration
+func (t *TUI) wakeUp() {
+	select {
+	case t.wake <- true:
+	default:
+	}
+}
+
+// runEvents calls functions posted to the UI goroutine and then redraws the
+// interface or panes that were invalidated
+func (t *TUI) runEvents() {
+	for {
+		t.eventsMu.Lock()
+		es := t.events
+		t.events = nil
+		t.eventsMu.Unlock()
+
+		if len(es) == 0 {
+			t.render()
+			return
+		}
+		for _, f := range es {
+			t.callEvent(f)
+		}
+	}
+}
+
+// queue posts function changing panes to the UI goroutine and requests the
+// interface to be redrawn after it. When TUI is not running yet, the
+// function is called straight away.
+func (t *TUI) queue(f func()) {
+	if t == nil || !t.isRunning() {
+		f()
+		return
+	}
+	t.Post(func() {
+		f()
+		t.Refresh()
+	})
+}
+
+// draw clears the terminal and draws all the panes and overlays on top
+func (t *TUI) draw() {
+	t.clear()
+	if t.onDraw != nil {
+		t.handleResult(t.callOnDraw())
+	}
+	t.getVisiblePane().Draw()
+	for _, o := range t.overlays {
+		o.draw()
+	}
+}
+
+// iterate calls Iterate on the main pane and overlays, and draws overlays
+// again when panes under them printed out something
+func (t *TUI) iterate(now time.Time) {
+	t.overdrawn = false
+	t.getVisiblePane().iterate(now)
+	for _, o := range t.overlays {
+		o.pane.iterate(now)
+	}
+	if t.overdrawn {
+		for _, o := range t.overlays {
+			o.draw()
+		}
+	}
+}
+
+// getVisiblePane returns zoomed pane or the main pane when nothing is zoomed
+func (t *TUI) getVisiblePane() *TUIPane {
+	if t.zoomed != nil {
+		return t.zoomed
+	}
+	return t.pane
+}
+
+// layout sets sizes of the main pane, zoomed pane and overlays and marks
+// panes that are hidden under the overlays so they are not iterated. Main
+// pane is always laid out so the sizes are correct when zoom is gone.
+func (t *TUI) layout() {
+	t.pane.SetWidth(t.w)
+	t.pane.SetHeight(t.h)
+	if t.zoomed != nil {
+		t.zoomed.SetLeft(0)
+		t.zoomed.SetTop(0)
+		t.zoomed.SetWidth(t.w)
+		t.zoomed.SetHeight(t.h)
+	}
+	if v := t.getVisiblePane(); t.tooSmallMessage && (t.w < v.getRequiredWidth() || t.h < v.getRequiredHeight()) {
+		v.tooSmall = true
+	}
+	for _, o := range t.overlays {
+		o.layout()
+	}
+	t.getVisiblePane().setCovered(t.overlays)
+	for i, o := range t.overlays {
+		o.pane.setCovered(t.overlays[i+1:])
+	}
+	t.resizeTerminals()
+}
+
+// sortOverlays sorts overlays by z-order
+func (t *TUI) sortOverlays() {
+	sort.SliceStable(t.overlays, func(i, j int) bool {
+		return t.overlays[i].z < t.overlays[j].z
+	})
+}
+
+// getModalOverlay returns the top modal overlay or nil
+func (t *TUI) getModalOverlay() *TUIOverlay {
+	for i := len(t.overlays) - 1; i >= 0; i-- {
+		if t.overlays[i].modal {
+			return t.overlays[i]
 		}
-		t.pane.Iterate()
-		time.Sleep(time.Millisecond * time.Duration(t.loopSleep))
 	}
+	return nil
 }
 
-// startStdioLoop creates a loop that will get keyboard input
-func (t *TUI) startStdioLoop() {
-	var b []byte = make([]byte, 1)
+// startStdioLoop creates a loop that will get keyboard input and pass it to
+// the UI goroutine. Input is split into keys which are passed one by one,
+// while special keys (eg. arrows) that send more than one byte are passed
+// together. When input ends, interface is stopped. Loop returns when done
+// is closed (main loop stops) and then it closes exited.
+func (t *TUI) startStdioLoop(done chan bool, exited chan bool) {
+	defer close(exited)
+	rd := t.getReader()
+	var rest []byte
 	for {
-		os.Stdin.Read(b)
-		if t.onKeyPress != nil {
-			t.onKeyPress(t, b)
+		var b []byte
+		var ok bool
+		select {
+		case <-done:
+			return
+		case b, ok = <-rd.ch:
+		}
+		if ok {
+			var keys [][]byte
+			keys, rest = splitKeys(append(rest, b...), len(b) == cap(b))
+			t.Post(func() {
+				for _, k := range keys {
+					t.input(k)
+				}
+			})
+			continue
+		}
+		if rd.err == io.EOF {
+			t.Stop(0)
+			return
+		}
+		t.Post(func() {
+			t.fail(RESULT_ERROR, rd.err)
+		})
+		return
+	}
+}
+
+// input handles bytes that came from the keyboard: mouse events, keys
+// captured by modal overlay, key bindings, terminal widget in the pane that
+// has focus, keys sending signals (when terminal widgets turned them off)
+// and then onKeyPress
+func (t *TUI) input(b []byte) {
+	t.record(ASCIICAST_INPUT, string(b))
+	if es, ok := parseMouseEvents(b); ok {
+		for _, e := range es {
+			t.mouseEvent(e)
+		}
+		return
+	}
+	if o := t.getModalOverlay(); o != nil {
+		if o.onKeyPress != nil {
+			o.onKeyPress(o, b)
+		}
+		return
+	}
+	if f, ok := t.keys[string(b)]; ok {
+		f(t)
+		return
+	}
+	if p := t.focus; p != nil && p.terminal != nil && t.isPaneShown(p) && p.terminal.KeyPress(p, b) {
+		return
+	}
+	if t.noSignals && t.tty == os.Stdin && raiseSignal(string(b)) {
+		return
+	}
+	if t.onKeyPress != nil {
+		t.onKeyPress(t, b)
+	}
+}
+
+// mouseEvent passes mouse event to panes (eg. tab bar click) and then to
+// onMouse
+func (t *TUI) mouseEvent(e *TUIMouseEvent) {
+	if t.drag != nil {
+		if e.Motion {
+			t.moveDrag(e)
+		} else if !e.Pressed {
+			t.drag = nil
+			// after the split value is changed by moveDrag
+			t.Post(t.saveLayoutState)
+		}
+		return
+	}
+	for i := len(t.overlays) - 1; i >= 0; i-- {
+		o := t.overlays[i]
+		if o.pane.contains(e.X, e.Y) {
+			o.pane.mouseEvent(e)
+			return
+		}
+		if o.modal {
+			return
+		}
+	}
+	if t.getVisiblePane().mouseEvent(e) {
+		return
+	}
+	if e.Pressed && !e.Motion && e.Button == MOUSE_LEFT {
+		if t.startDrag(e) {
+			return
+		}
+		if p := t.GetPaneAt(e.X, e.Y); p != nil {
+			t.focus = p
 		}
 	}
+	if t.onMouse != nil {
+		t.onMouse(t, e)
+	}
 }
 
-// getSize gets terminal size by calling stty command
+// getSize gets terminal size by calling stty command or returns size set
+// with SetSize when there is no tty
 func (t *TUI) getSize() (int, int, error) {
+	if t.tty == nil {
+		return t.sizeW, t.sizeH, nil
+	}
 	cmd := exec.Command("stty", "size")
-	cmd.Stdin = os.Stdin
+	cmd.Stdin = t.tty
 	out, err := cmd.Output()
 	if err != nil {
 		return 0, 0, err
@@ -81,18 +355,38 @@ func (t *TUI) getSize() (int, int, error) {
 	return w, h, nil
 }
 
+// fitString cuts string to w characters or pads it with spaces so it is
+// exactly w characters long
+func fitString(s string, w int) string {
+	if w <= 0 {
+		return ""
+	}
+	r := []rune(s)
+	if len(r) > w {
+		return string(r[:w])
+	}
+	return s + strings.Repeat(" ", w-len(r))
+}
+
 // refreshSize gets terminal size and caches it
 func (t *TUI) refreshSize() bool {
+	t.sizeChecked = time.Now()
 	w, h, err := t.getSize()
 	if err != nil {
 		return false
 	}
 	if t.w != w || t.h != h {
+		t.stdoutMu.Lock()
 		t.w = w
 		t.h = h
+		if t.screen != nil {
+			t.screen.Resize(w, h)
+		}
+		t.stdoutMu.Unlock()
+		t.record(ASCIICAST_RESIZE, strconv.Itoa(w)+"x"+strconv.Itoa(h))
 
-		t.pane.SetWidth(w)
-		t.pane.SetHeight(h)
+		t.applyBreakpoint()
+		t.layout()
 		return true
 	}
 	return false
@@ -0,0 +1,51 @@
+// Package socket creates Unix domain sockets shared by the interface control
+// and session server.
+package socket
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// Listen starts listening on Unix socket at path that only the current user
+// can use. Socket left by a process that has not stopped cleanly is removed,
+// but an error is returned when another process is listening on it or the
+// file is not a socket.
+func Listen(path string) (net.Listener, error) {
+	err := removeStale(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Chmod(path, 0600)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// removeStale removes socket file that nothing listens on. It returns an
+// error when file is not a socket or a process is listening on it.
+func removeStale(path string) error {
+	fi, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSocket == 0 {
+		return errors.New(path + " exists and is not a socket")
+	}
+	c, err := net.Dial("unix", path)
+	if err == nil {
+		c.Close()
+		return errors.New("another process is already running at " + path)
+	}
+	return os.Remove(path)
+}
@@ -0,0 +1,80 @@
+package session
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+)
+
+// Client is attached to a Server. Reading from it returns TUI output and
+// writing to it sends input (key presses).
+type Client struct {
+	conn     net.Conn
+	readOnly bool
+	mu       sync.Mutex
+}
+
+// IsReadOnly returns true when client only views the interface
+func (c *Client) IsReadOnly() bool {
+	return c.readOnly
+}
+
+// Read reads TUI output
+func (c *Client) Read(b []byte) (int, error) {
+	return c.conn.Read(b)
+}
+
+// Write sends input to TUI. It is ignored by the server when client is
+// read-only.
+func (c *Client) Write(b []byte) (int, error) {
+	for i := 0; i < len(b); i += 0xffff {
+		e := i + 0xffff
+		if e > len(b) {
+			e = len(b)
+		}
+		err := c.writeFrame(FRAME_INPUT, b[i:e])
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(b), nil
+}
+
+// Resize sends client's terminal size
+func (c *Client) Resize(w int, h int) error {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint16(b, uint16(w))
+	binary.BigEndian.PutUint16(b[2:], uint16(h))
+	return c.writeFrame(FRAME_RESIZE, b)
+}
+
+// Close detaches the client
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// writeFrame sends frame to the server
+func (c *Client) writeFrame(t byte, b []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return writeFrame(c.conn, t, b)
+}
+
+// Attach connects to the server listening on Unix socket at path
+func Attach(path string, readOnly bool) (*Client, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Client{conn: conn, readOnly: readOnly}
+	ro := byte(0)
+	if readOnly {
+		ro = 1
+	}
+	err = c.writeFrame(FRAME_HELLO, []byte{ro})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,40 @@
+package session
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Frame types sent by the client to the server. First frame must be hello
+// with one byte which is 1 for read-only clients. Resize has width and
+// height as two 16-bit numbers and input has bytes of the pressed keys.
+const FRAME_HELLO = 'h'
+const FRAME_RESIZE = 'r'
+const FRAME_INPUT = 'i'
+
+// writeFrame writes frame type, payload length and payload
+func writeFrame(w io.Writer, t byte, b []byte) error {
+	if len(b) > 0xffff {
+		return errors.New("frame too long")
+	}
+	h := []byte{t, 0, 0}
+	binary.BigEndian.PutUint16(h[1:], uint16(len(b)))
+	_, err := w.Write(append(h, b...))
+	return err
+}
+
+// readFrame reads frame and returns its type and payload
+func readFrame(r io.Reader) (byte, []byte, error) {
+	h := make([]byte, 3)
+	_, err := io.ReadFull(r, h)
+	if err != nil {
+		return 0, nil, err
+	}
+	b := make([]byte, binary.BigEndian.Uint16(h[1:]))
+	_, err = io.ReadFull(r, b)
+	if err != nil {
+		return 0, nil, err
+	}
+	return h[0], b, nil
+}
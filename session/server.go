@@ -0,0 +1,196 @@
+// Package session keeps a terminalui interface running in a long-running
+// process while clients attach to it and detach from it through a Unix
+// domain socket, like tmux does. Terminal size of the client that attached
+// (or resized) most recently is used. Many clients can be attached at the
+// same time and some of them can be read-only viewers.
+package session
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	terminalui "github.com/go-phings/terminal-ui"
+	"github.com/go-phings/terminal-ui/internal/socket"
+)
+
+// Number of TUI writes queued for a client. Client that is too slow to
+// receive them is detached.
+const CLIENT_QUEUE = 1024
+
+// Server runs TUI and passes its output to all the attached clients, and
+// input from the clients that are not read-only back to the TUI
+type Server struct {
+	tui      *terminalui.TUI
+	path     string
+	listener net.Listener
+	clients  map[*serverClient]bool
+	input    *io.PipeWriter
+	mu       sync.Mutex
+}
+
+// serverClient is a client attached to the server. TUI output is sent to
+// it by its own goroutine.
+type serverClient struct {
+	conn     net.Conn
+	readOnly bool
+	out      chan []byte
+}
+
+// GetTUI returns TUI that is run by the server
+func (s *Server) GetTUI() *terminalui.TUI {
+	return s.tui
+}
+
+// GetPath returns path of the Unix socket
+func (s *Server) GetPath() string {
+	return s.path
+}
+
+// GetClients returns number of attached clients
+func (s *Server) GetClients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.clients)
+}
+
+// Run listens on the Unix socket and runs TUI. It returns when TUI stops,
+// after all the clients are detached and socket is removed. Socket can be
+// used only by the user that runs the server. Socket left by a server that
+// has not stopped cleanly is removed, but an error is returned when another
+// server is listening on it.
+func (s *Server) Run() (int, error) {
+	l, err := socket.Listen(s.path)
+	if err != nil {
+		return terminalui.RESULT_ERROR, err
+	}
+	s.listener = l
+
+	in, inw := io.Pipe()
+	s.input = inw
+	s.tui.SetInput(in)
+	s.tui.SetOutput(s)
+	s.tui.SetSize(80, 24)
+
+	go s.accept()
+	code := s.tui.Run(nil, nil)
+
+	l.Close()
+	in.Close()
+	s.mu.Lock()
+	for c := range s.clients {
+		s.removeClient(c)
+	}
+	s.mu.Unlock()
+	os.Remove(s.path)
+	return code, s.tui.GetError()
+}
+
+// Write queues TUI output for all the attached clients, so it never waits
+// for them. Clients which queue is full are detached.
+func (s *Server) Write(b []byte) (int, error) {
+	o := make([]byte, len(b))
+	copy(o, b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for c := range s.clients {
+		select {
+		case c.out <- o:
+		default:
+			s.removeClient(c)
+		}
+	}
+	return len(b), nil
+}
+
+// removeClient detaches the client. It has to be called with mu locked.
+func (s *Server) removeClient(c *serverClient) {
+	if s.clients[c] {
+		delete(s.clients, c)
+		close(c.out)
+	}
+	c.conn.Close()
+}
+
+// write sends queued TUI output to the client. When it fails, connection
+// is closed and the rest of the queue is dropped.
+func (c *serverClient) write() {
+	failed := false
+	for b := range c.out {
+		if failed {
+			continue
+		}
+		c.conn.SetWriteDeadline(time.Now().Add(time.Second * 5))
+		_, err := c.conn.Write(b)
+		if err != nil {
+			c.conn.Close()
+			failed = true
+		}
+	}
+}
+
+// accept accepts clients until the listener is closed
+func (s *Server) accept() {
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleClient(c)
+	}
+}
+
+// handleClient reads hello frame, attaches the client and then passes its
+// size changes and input to TUI until it detaches
+func (s *Server) handleClient(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	t, b, err := readFrame(r)
+	if err != nil || t != FRAME_HELLO || len(b) != 1 {
+		conn.Close()
+		return
+	}
+	c := &serverClient{conn: conn, readOnly: b[0] == 1, out: make(chan []byte, CLIENT_QUEUE)}
+	go c.write()
+	s.mu.Lock()
+	s.clients[c] = true
+	s.mu.Unlock()
+	// new client needs the whole screen
+	s.tui.Refresh()
+
+	for {
+		t, b, err := readFrame(r)
+		if err != nil {
+			break
+		}
+		if c.readOnly {
+			continue
+		}
+		switch t {
+		case FRAME_RESIZE:
+			if len(b) == 4 {
+				s.tui.SetSize(int(binary.BigEndian.Uint16(b)), int(binary.BigEndian.Uint16(b[2:])))
+			}
+		case FRAME_INPUT:
+			s.input.Write(b)
+		}
+	}
+
+	s.mu.Lock()
+	s.removeClient(c)
+	s.mu.Unlock()
+}
+
+// NewServer returns new instance of Server that runs TUI and listens on Unix
+// socket at path. TUI must not be running.
+func NewServer(t *terminalui.TUI, path string) *Server {
+	s := &Server{
+		tui:     t,
+		path:    path,
+		clients: map[*serverClient]bool{},
+	}
+	return s
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	terminalui "github.com/go-phings/terminal-ui"
+)
+
+// waitFor waits until f returns true
+func waitFor(t *testing.T, what string, f func() bool) {
+	for end := time.Now().Add(5 * time.Second); time.Now().Before(end); time.Sleep(10 * time.Millisecond) {
+		if f() {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+// startServer runs server with a TUI that stops on 'q'
+func startServer(t *testing.T, path string) (*Server, chan error) {
+	tui := terminalui.NewTUI()
+	tui.GetPane().SetOnDraw(func(p *terminalui.TUIPane) int {
+		p.Write(0, 0, "hello", false)
+		return terminalui.RESULT_OK
+	})
+	tui.SetOnKeyPress(func(tui *terminalui.TUI, b []byte) {
+		if string(b) == "q" {
+			tui.Stop(0)
+		}
+	})
+	s := NewServer(tui, path)
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Run()
+		done <- err
+	}()
+	waitFor(t, "socket", func() bool {
+		c, err := net.Dial("unix", path)
+		if err == nil {
+			c.Close()
+		}
+		return err == nil
+	})
+	return s, done
+}
+
+func TestServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "s.sock")
+	// socket left by a server that crashed
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	s, done := startServer(t, path)
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("socket mode is %v, want 0600", fi.Mode().Perm())
+	}
+	if _, err := NewServer(terminalui.NewTUI(), path).Run(); err == nil || !strings.Contains(err.Error(), "already running") {
+		t.Errorf("second server got %v", err)
+	}
+
+	// client that never reads is detached without blocking the others
+	stuck, err := Attach(path, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stuck.Close()
+	c, err := Attach(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	go io.Copy(io.Discard, c)
+	waitFor(t, "clients", func() bool {
+		return s.GetClients() == 2
+	})
+	for i := 0; i < CLIENT_QUEUE*4 && s.GetClients() == 2; i++ {
+		s.Write([]byte(strings.Repeat("x", 1024)))
+		time.Sleep(100 * time.Microsecond)
+	}
+	waitFor(t, "stuck client to be detached", func() bool {
+		return s.GetClients() == 1
+	})
+
+	c.Write([]byte("q"))
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not stop")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket was not removed")
+	}
+}
+
+func TestServerNotSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("data"), 0600)
+	if _, err := NewServer(terminalui.NewTUI(), path).Run(); err == nil {
+		t.Error("file that is not a socket was replaced")
+	}
+}
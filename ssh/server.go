@@ -0,0 +1,122 @@
+// Package ssh serves terminal interfaces made with terminalui over SSH. Each
+// client that connects gets its own TUI instance which is sized from the
+// client's pty and window changes, and which reads keyboard input from the
+// SSH channel.
+package ssh
+
+import (
+	"net"
+	"sync"
+
+	terminalui "github.com/go-phings/terminal-ui"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Server is an SSH server that creates a TUI for every session with a func
+// passed to NewServer. Authentication and host keys are set in ServerConfig.
+type Server struct {
+	config   *gossh.ServerConfig
+	newTUI   func(*Session) *terminalui.TUI
+	listener net.Listener
+	sessions map[*Session]bool
+	mu       sync.Mutex
+}
+
+// ListenAndServe listens on TCP address and serves incoming connections
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve accepts connections on the listener and handles each of them in a
+// separate goroutine. It returns when listener fails or is closed.
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go s.handleConn(c)
+	}
+}
+
+// Close stops listening and closes all the sessions
+func (s *Server) Close() error {
+	s.mu.Lock()
+	l := s.listener
+	s.mu.Unlock()
+	var err error
+	if l != nil {
+		err = l.Close()
+	}
+	for _, ses := range s.GetSessions() {
+		ses.conn.Close()
+	}
+	return err
+}
+
+// GetSessions returns sessions of connected clients
+func (s *Server) GetSessions() []*Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ss := make([]*Session, 0, len(s.sessions))
+	for ses := range s.sessions {
+		ss = append(ss, ses)
+	}
+	return ss
+}
+
+// handleConn does SSH handshake and accepts session channels
+func (s *Server) handleConn(c net.Conn) {
+	conn, chans, reqs, err := gossh.NewServerConn(c, s.config)
+	if err != nil {
+		c.Close()
+		return
+	}
+	go gossh.DiscardRequests(reqs)
+	for nc := range chans {
+		if nc.ChannelType() != "session" {
+			nc.Reject(gossh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, reqs, err := nc.Accept()
+		if err != nil {
+			continue
+		}
+		ses := &Session{
+			server:  s,
+			conn:    conn,
+			channel: ch,
+		}
+		s.mu.Lock()
+		s.sessions[ses] = true
+		s.mu.Unlock()
+		go ses.handleRequests(reqs)
+	}
+	conn.Close()
+}
+
+// removeSession forgets session that has ended
+func (s *Server) removeSession(ses *Session) {
+	s.mu.Lock()
+	delete(s.sessions, ses)
+	s.mu.Unlock()
+}
+
+// NewServer returns new instance of Server. Function f is called for every
+// new session and has to return a new TUI (with panes, widgets etc.) that
+// is not running.
+func NewServer(c *gossh.ServerConfig, f func(*Session) *terminalui.TUI) *Server {
+	s := &Server{
+		config:   c,
+		newTUI:   f,
+		sessions: map[*Session]bool{},
+	}
+	return s
+}
@@ -0,0 +1,171 @@
+package ssh
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	terminalui "github.com/go-phings/terminal-ui"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// syncBuffer is a buffer that output of the session is written to from
+// another goroutine
+type syncBuffer struct {
+	b  bytes.Buffer
+	mu sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.b.String()
+}
+
+// startServer starts Server on a random port and returns its address
+func startServer(t *testing.T, f func(*Session) *terminalui.TUI) (*Server, string) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &gossh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(config, f)
+	go s.Serve(l)
+	t.Cleanup(func() {
+		s.Close()
+	})
+	return s, l.Addr().String()
+}
+
+// dial connects to the server and opens a session
+func dial(t *testing.T, addr string) *gossh.Session {
+	c, err := gossh.Dial("tcp", addr, &gossh.ClientConfig{
+		User:            "tester",
+		HostKeyCallback: gossh.InsecureIgnoreHostKey(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		c.Close()
+	})
+	ses, err := c.NewSession()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ses
+}
+
+// waitFor waits until f returns true
+func waitFor(t *testing.T, what string, f func() bool) {
+	for end := time.Now().Add(5 * time.Second); time.Now().Before(end); time.Sleep(10 * time.Millisecond) {
+		if f() {
+			return
+		}
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestServer(t *testing.T) {
+	s, addr := startServer(t, func(ses *Session) *terminalui.TUI {
+		tui := terminalui.NewTUI()
+		tui.GetPane().SetOnDraw(func(p *terminalui.TUIPane) int {
+			p.Write(0, 0, "hello "+ses.GetUser()+" "+ses.GetTerm(), false)
+			return terminalui.RESULT_OK
+		})
+		tui.SetOnKeyPress(func(tui *terminalui.TUI, b []byte) {
+			if string(b) == "q" {
+				tui.Stop(0)
+			}
+		})
+		return tui
+	})
+
+	ses := dial(t, addr)
+	out := &syncBuffer{}
+	ses.Stdout = out
+	in, err := ses.StdinPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ses.RequestPty("xterm", 10, 40, gossh.TerminalModes{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := ses.Shell(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "interface", func() bool {
+		return strings.Contains(out.String(), "hello tester xterm")
+	})
+
+	ss := s.GetSessions()
+	if len(ss) != 1 || ss[0].GetTUI() == nil {
+		t.Fatalf("got %d sessions, want 1 with TUI", len(ss))
+	}
+	size := func() (int, int) {
+		var w, h int
+		tui := ss[0].GetTUI()
+		tui.PostAndWait(func() {
+			w, h = tui.GetWidth(), tui.GetHeight()
+		})
+		return w, h
+	}
+	if w, h := size(); w != 40 || h != 10 {
+		t.Errorf("size is %dx%d, want 40x10", w, h)
+	}
+	if err := ses.WindowChange(12, 50); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, "window change", func() bool {
+		w, h := size()
+		return w == 50 && h == 12
+	})
+
+	in.Write([]byte("q"))
+	if err := ses.Wait(); err != nil {
+		t.Errorf("session ended with %v", err)
+	}
+	waitFor(t, "session to be removed", func() bool {
+		return len(s.GetSessions()) == 0
+	})
+}
+
+func TestServerWithoutPty(t *testing.T) {
+	_, addr := startServer(t, func(ses *Session) *terminalui.TUI {
+		return terminalui.NewTUI()
+	})
+	ses := dial(t, addr)
+	r, err := ses.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ses.Shell(); err == nil {
+		t.Error("shell was started without pty")
+	}
+	b := make([]byte, 64)
+	n, _ := r.Read(b)
+	if !strings.Contains(string(b[:n]), "pty is required") {
+		t.Errorf("got %q, want message that pty is required", b[:n])
+	}
+}
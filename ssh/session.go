@@ -0,0 +1,188 @@
+package ssh
+
+import (
+	"io"
+	"net"
+	"sync"
+
+	terminalui "github.com/go-phings/terminal-ui"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// Session is a connected client with its own TUI. TUI is created when the
+// client requests a shell, which requires a pty.
+type Session struct {
+	server  *Server
+	conn    *gossh.ServerConn
+	channel gossh.Channel
+	tui     *terminalui.TUI
+	term    string
+	pty     bool
+	width   int
+	height  int
+	mu      sync.Mutex
+}
+
+// ptyRequest is the payload of the "pty-req" request
+type ptyRequest struct {
+	Term    string
+	Columns uint32
+	Rows    uint32
+	Width   uint32
+	Height  uint32
+	Modes   string
+}
+
+// windowChange is the payload of the "window-change" request
+type windowChange struct {
+	Columns uint32
+	Rows    uint32
+	Width   uint32
+	Height  uint32
+}
+
+// exitStatus is the payload of the "exit-status" request
+type exitStatus struct {
+	Status uint32
+}
+
+// GetUser returns name of the user that logged in
+func (s *Session) GetUser() string {
+	return s.conn.User()
+}
+
+// GetRemoteAddr returns client's address
+func (s *Session) GetRemoteAddr() net.Addr {
+	return s.conn.RemoteAddr()
+}
+
+// GetTerm returns value of TERM sent by the client
+func (s *Session) GetTerm() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.term
+}
+
+// GetTUI returns TUI of the session or nil when it is not started yet
+func (s *Session) GetTUI() *terminalui.TUI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tui
+}
+
+// Close stops TUI of the session and closes its channel
+func (s *Session) Close() {
+	if t := s.GetTUI(); t != nil {
+		t.Stop(0)
+		return
+	}
+	s.channel.Close()
+}
+
+// handleRequests replies to session requests: pty and window size changes,
+// and starting the shell
+func (s *Session) handleRequests(reqs <-chan *gossh.Request) {
+	for r := range reqs {
+		ok := false
+		switch r.Type {
+		case "pty-req":
+			p := &ptyRequest{}
+			if gossh.Unmarshal(r.Payload, p) == nil {
+				s.mu.Lock()
+				s.term = p.Term
+				s.pty = true
+				s.mu.Unlock()
+				s.resize(int(p.Columns), int(p.Rows))
+				ok = true
+			}
+		case "window-change":
+			p := &windowChange{}
+			if gossh.Unmarshal(r.Payload, p) == nil {
+				s.resize(int(p.Columns), int(p.Rows))
+				ok = true
+			}
+		case "shell":
+			ok = s.start()
+		}
+		if r.WantReply {
+			r.Reply(ok, nil)
+		}
+	}
+	if s.GetTUI() == nil {
+		s.channel.Close()
+		s.server.removeSession(s)
+	}
+}
+
+// resize sets size of the client's terminal. Clients that do not know the
+// size send zeros and get 80x24.
+func (s *Session) resize(w int, h int) {
+	if w <= 0 {
+		w = 80
+	}
+	if h <= 0 {
+		h = 24
+	}
+	s.mu.Lock()
+	s.width = w
+	s.height = h
+	t := s.tui
+	s.mu.Unlock()
+	if t != nil {
+		t.SetSize(w, h)
+	}
+}
+
+// start creates TUI and runs it in a separate goroutine. It returns false
+// when there is no pty or TUI is already started.
+func (s *Session) start() bool {
+	s.mu.Lock()
+	started, pty, w, h := s.tui != nil, s.pty, s.width, s.height
+	s.mu.Unlock()
+	if started {
+		return false
+	}
+	if !pty {
+		io.WriteString(s.channel, "pty is required\r\n")
+		return false
+	}
+	// requests are handled one by one so size cannot change in the meantime
+	t := s.server.newTUI(s)
+	t.SetInput(&crlfReader{r: s.channel})
+	t.SetOutput(s.channel)
+	t.SetSize(w, h)
+	s.mu.Lock()
+	s.tui = t
+	s.mu.Unlock()
+	go s.run(t)
+	return true
+}
+
+// run runs TUI and closes the channel when it stops, sending its exit code
+// to the client
+func (s *Session) run(t *terminalui.TUI) {
+	code := t.Run(nil, nil)
+	if code < 0 {
+		code = 1
+	}
+	s.channel.SendRequest("exit-status", false, gossh.Marshal(&exitStatus{Status: uint32(code)}))
+	s.channel.Close()
+	s.server.removeSession(s)
+}
+
+// crlfReader turns carriage returns into new lines, which is what terminal
+// does with enter key in cbreak mode
+type crlfReader struct {
+	r io.Reader
+}
+
+// Read reads from the underlying reader and replaces carriage returns
+func (c *crlfReader) Read(b []byte) (int, error) {
+	n, err := c.r.Read(b)
+	for i := 0; i < n; i++ {
+		if b[i] == '\r' {
+			b[i] = '\n'
+		}
+	}
+	return n, err
+}
@@ -1,50 +1,156 @@
 package terminalui
 
 import (
-	"fmt"
+	"errors"
+	"io"
+	"net"
 	"os"
 	"os/exec"
 	"strconv"
+	"sync"
+	"time"
 )
 
 // TUI is main interface definition. It has current terminal width and height, pointer to main pane,
 // pointer to a function that is triggered when interface is being drawn (that happens when app is
 // started and when terminal size is changed), and finally pointers to standard output and standard
 // error File instances.
+// Panes are drawn and all the attached funcs are called on a single UI goroutine. Other goroutines
+// should use Post to update panes and widgets.
 type TUI struct {
-	stdout     *os.File
-	stderr     *os.File
-	h          int
-	w          int
-	pane       *TUIPane
-	onDraw     func(*TUI) int
-	onKeyPress func(*TUI, []byte)
-	loopSleep  int
+	stdout          *os.File
+	stderr          *os.File
+	in              io.Reader
+	reader          *tuiReader
+	out             io.Writer
+	tty             *os.File
+	sizeW           int
+	sizeH           int
+	h               int
+	w               int
+	pane            *TUIPane
+	onDraw          func(*TUI) int
+	onKeyPress      func(*TUI, []byte)
+	onMouse         func(*TUI, *TUIMouseEvent)
+	keys            map[string]func(*TUI)
+	widgets         map[string]func(*TUIPane)
+	appName         string
+	savedLayout     []byte
+	breakpoints     []*tuiBreakpoint
+	breakpoint      *tuiBreakpoint
+	defaultLayout   *TUILayout
+	breakpointPanes map[string]*TUIPane
+	mouse           bool
+	loopSleep       int
+	overlays        []*TUIOverlay
+	zoomed          *TUIPane
+	focus           *TUIPane
+	drag            *TUIPane
+	dragOffset      int
+	onSplitResize   func(*TUIPane, int, int)
+	onTooSmall      func(*TUIPane, int, int, int, int)
+	tooSmallMessage bool
+	onPanic         func(*TUIPaneError) bool
+	wake            chan bool
+	running         bool
+	stopped         bool
+	events          []func()
+	eventsMu        sync.Mutex
+	redraw          bool
+	dirty           map[*TUIPane]bool
+	overdrawn       bool
+	maxFPS          int
+	lastRender      time.Time
+	renderWait      bool
+	timers          map[*TUITimer]bool
+	done            chan bool
+	stopping        bool
+	exitCode        int
+	err             error
+	resizeSignal    chan os.Signal
+	noSignals       bool
+	sizeChecked     time.Time
+	control         net.Listener
+	controlConns    map[net.Conn]bool
+	controlMu       sync.Mutex
+	stdoutMu        sync.Mutex
+	recorder        *tuiRecorder
+	screen          *TUIScreen
 }
 
 // NewTUI creates new instance of TUI and returns it
 func NewTUI() *TUI {
-	t := &TUI{}
+	t := &TUI{
+		keys:    map[string]func(*TUI){},
+		widgets: map[string]func(*TUIPane){},
+		wake:    make(chan bool, 1),
+	}
 	p := NewTUIPane("main", t)
 	t.SetPane(p)
 	t.SetLoopSleep(1000)
 	return t
 }
 
-// Run clears the terminal and starts program's main loop
+// Run clears the terminal and starts program's main loop. It returns when
+// any of onDraw or onIterate funcs returns RESULT_EXIT (with 0), Stop is
+// called, input ends, or there is an error (with its code, see GetError),
+// after the terminal is restored.
+// Interface is printed to stdout and keyboard input is read from os.Stdin,
+// unless other output and input are set with SetOutput and SetInput.
 func (t *TUI) Run(stdout *os.File, stderr *os.File) int {
 	t.stdout = stdout
 	t.stderr = stderr
+	if t.out == nil && stdout != nil {
+		t.out = stdout
+	}
+	if t.in == nil {
+		t.in = os.Stdin
+		if t.tty == nil {
+			t.tty = os.Stdin
+		}
+	}
 
-	t.initTTY()
+	t.stopping = false
+	t.exitCode = 0
+	t.err = nil
+
+	t.restoreLayoutState()
+	err := t.initTTY()
+	if err != nil {
+		t.err = err
+		return RESULT_ERROR
+	}
 	t.clear()
+	t.done = make(chan bool)
+	t.setRunning(true)
+	if t.mouse {
+		t.print("\u001b[?1000h\u001b[?1002h\u001b[?1006h")
+	}
 
-	done := make(chan bool)
+	stdio := make(chan bool)
 	go t.startMainLoop()
-	go t.startStdioLoop()
-	<-done
+	go t.startStdioLoop(t.done, stdio)
+	t.watchResize()
+	t.startTimers()
+	<-t.done
+	<-stdio
 
-	return 0
+	t.stopTimers(nil)
+	t.closeTerminals()
+	t.unwatchResize()
+	t.CloseControl()
+	t.restoreTTY()
+	t.flushRecording()
+	t.setRunning(false)
+	return t.exitCode
+}
+
+// Stop makes Run return with the code after the terminal is restored. Unlike
+// Exit, it does not end the program so other TUIs can keep running.
+func (t *TUI) Stop(code int) {
+	t.Post(func() {
+		t.quit(code)
+	})
 }
 
 // GetStdout returns stdout property
@@ -72,6 +178,21 @@ func (t *TUI) GetHeight() int {
 	return t.h
 }
 
+// GetInput returns reader that keyboard input is read from
+func (t *TUI) GetInput() io.Reader {
+	return t.in
+}
+
+// GetOutput returns writer that interface is printed to
+func (t *TUI) GetOutput() io.Writer {
+	return t.out
+}
+
+// GetTTY returns terminal device that is set up with stty
+func (t *TUI) GetTTY() *os.File {
+	return t.tty
+}
+
 // GetLoopSleep returns delay between each iteration of main loop
 func (t *TUI) GetLoopSleep() int {
 	return t.loopSleep
@@ -88,33 +209,274 @@ func (t *TUI) SetOnKeyPress(f func(*TUI, []byte)) {
 	t.onKeyPress = f
 }
 
+// SetOnTooSmall attaches function that will be triggered, instead of
+// printing out '!', when pane is too small to be drawn. It gets the pane,
+// its required width and height, and its actual width and height.
+func (t *TUI) SetOnTooSmall(f func(*TUIPane, int, int, int, int)) {
+	t.onTooSmall = f
+}
+
+// SetTooSmallMessage sets whether "terminal too small" message is printed
+// out on the whole terminal window when it is smaller than all the panes
+// need. By default, only the panes that do not fit are marked with '!'.
+func (t *TUI) SetTooSmallMessage(b bool) {
+	t.tooSmallMessage = b
+	t.Refresh()
+}
+
+// SetOnMouse attaches function that will be triggered when mouse button is
+// pressed or released. Mouse has to be enabled with SetMouse.
+func (t *TUI) SetOnMouse(f func(*TUI, *TUIMouseEvent)) {
+	t.onMouse = f
+}
+
+// SetMouse enables or disables mouse reporting. It has to be called before Run.
+func (t *TUI) SetMouse(b bool) {
+	t.mouse = b
+}
+
+// BindKey attaches function that will be triggered instead of onKeyPress
+// when specific key (eg. KEY_ALT_LEFT) is pressed
+func (t *TUI) BindKey(k string, f func(*TUI)) {
+	t.keys[k] = f
+}
+
+// UnbindKey removes function attached with BindKey
+func (t *TUI) UnbindKey(k string) {
+	delete(t.keys, k)
+}
+
+// SetInput sets reader that keyboard input is read from instead of os.Stdin
+// (eg. a pty or a network connection). It has to be called before Run. When
+// input ends, Run returns.
+func (t *TUI) SetInput(r io.Reader) {
+	t.in = r
+	t.reader = nil
+}
+
+// SetOutput sets writer that interface is printed to instead of stdout
+// passed to Run. It has to be called before Run.
+func (t *TUI) SetOutput(w io.Writer) {
+	t.out = w
+}
+
+// SetTTY sets terminal device which is switched to cbreak and no echo mode,
+// and which size is read with stty. It has to be called before Run. When
+// input is set with SetInput and there is no tty, size has to be set with
+// SetSize.
+func (t *TUI) SetTTY(f *os.File) {
+	t.tty = f
+}
+
+// SetSize sets terminal size when there is no tty (eg. it comes from a
+// remote client) and redraws the interface
+func (t *TUI) SetSize(w int, h int) {
+	t.Post(func() {
+		t.sizeW = w
+		t.sizeH = h
+		t.Refresh()
+	})
+}
+
 // SetPane sets the main terminal pane
 func (t *TUI) SetPane(p *TUIPane) {
 	t.pane = p
 }
 
-// SetLoopSleep sets the delay between each iteration of main loop
+// SetLoopSleep sets the delay between each iteration of main loop. Panes
+// are iterated on every tick, while redrawing panes that changed should be
+// requested with TUIPane.Invalidate.
 func (t *TUI) SetLoopSleep(s int) {
 	t.loopSleep = s
 }
 
-// Write prints out on the terminal window at a specified position
+// GetOverlays returns opened overlays sorted by z-order
+func (t *TUI) GetOverlays() []*TUIOverlay {
+	return t.overlays
+}
+
+// OpenOverlay shows floating pane on top of other panes
+func (t *TUI) OpenOverlay(o *TUIOverlay) {
+	t.queue(func() {
+		for _, x := range t.overlays {
+			if x == o {
+				return
+			}
+		}
+		t.overlays = append(t.overlays, o)
+		t.sortOverlays()
+	})
+}
+
+// CloseOverlay hides floating pane and redraws what was underneath
+func (t *TUI) CloseOverlay(o *TUIOverlay) {
+	t.queue(func() {
+		for i, x := range t.overlays {
+			if x == o {
+				t.overlays = append(t.overlays[:i], t.overlays[i+1:]...)
+				return
+			}
+		}
+	})
+}
+
+// GetFocus returns pane that has focus (it is set by SetFocus or by clicking
+// on a pane with mouse)
+func (t *TUI) GetFocus() *TUIPane {
+	return t.focus
+}
+
+// SetFocus sets the pane that has focus
+func (t *TUI) SetFocus(p *TUIPane) {
+	t.focus = p
+}
+
+// GetPaneAt returns pane (one that is not split) which is shown at specific
+// point on terminal window. Overlays are checked first.
+func (t *TUI) GetPaneAt(x int, y int) *TUIPane {
+	for i := len(t.overlays) - 1; i >= 0; i-- {
+		if t.overlays[i].pane.contains(x, y) {
+			return t.overlays[i].pane.getPaneAt(x, y)
+		}
+	}
+	return t.getVisiblePane().getPaneAt(x, y)
+}
+
+// GetZoomed returns pane that is zoomed or nil
+func (t *TUI) GetZoomed() *TUIPane {
+	return t.zoomed
+}
+
+// Zoom temporarily maximizes pane to fill the whole terminal window. Other
+// panes are not drawn nor iterated until Unzoom is called.
+func (t *TUI) Zoom(p *TUIPane) {
+	t.queue(func() {
+		t.zoom(p)
+	})
+}
+
+// zoom sets zoomed pane. It must be called on the UI goroutine.
+func (t *TUI) zoom(p *TUIPane) {
+	if p == t.pane {
+		t.zoomed = nil
+	} else {
+		t.zoomed = p
+	}
+}
+
+// Unzoom restores the original layout after Zoom
+func (t *TUI) Unzoom() {
+	t.Zoom(nil)
+}
+
+// ToggleZoom zooms pane or restores the layout when it is already zoomed.
+// Zoomed pane is checked on the UI goroutine.
+func (t *TUI) ToggleZoom(p *TUIPane) {
+	t.queue(func() {
+		if t.zoomed == p {
+			t.zoom(nil)
+		} else {
+			t.zoom(p)
+		}
+	})
+}
+
+// Refresh requests main loop to recalculate pane sizes and redraw the whole
+// interface as soon as possible
+func (t *TUI) Refresh() {
+	t.eventsMu.Lock()
+	t.redraw = true
+	t.eventsMu.Unlock()
+	t.wakeUp()
+}
+
+// Post adds function to be called on the UI goroutine, which is the one that
+// draws panes and calls all the attached funcs. It should be used to update
+// panes and widgets from other goroutines. When TUI is not running yet, the
+// function is called straight away. Functions posted after the main loop
+// stopped are dropped.
+func (t *TUI) Post(f func()) {
+	t.post(f)
+}
+
+// PostAndWait calls function on the UI goroutine (see Post) and waits until
+// it is done. It returns an error when the main loop stopped before calling
+// the function. It must not be called on the UI goroutine itself (eg. from
+// onDraw or onKeyPress) as it would never return.
+func (t *TUI) PostAndWait(f func()) error {
+	done := make(chan bool)
+	stopped, ok := t.post(func() {
+		defer close(done)
+		f()
+	})
+	if !ok {
+		return errors.New("interface is stopping")
+	}
+	select {
+	case <-done:
+		return nil
+	case <-stopped:
+	}
+	// function could have been called just before the loop stopped
+	select {
+	case <-done:
+		return nil
+	default:
+		return errors.New("interface is stopping")
+	}
+}
+
+// post adds function to events and returns channel that is closed when the
+// main loop stops. It returns false when the loop has already stopped.
+func (t *TUI) post(f func()) (chan bool, bool) {
+	t.eventsMu.Lock()
+	if !t.running {
+		t.eventsMu.Unlock()
+		f()
+		return nil, true
+	}
+	if t.stopped {
+		t.eventsMu.Unlock()
+		return nil, false
+	}
+	t.events = append(t.events, f)
+	stopped := t.done
+	t.eventsMu.Unlock()
+	t.wakeUp()
+	return stopped, true
+}
+
+// Write prints out on the terminal window at a specified position. Whole
+// string is written at once so it is safe to call from many goroutines.
 func (t *TUI) Write(x int, y int, s string) {
-	fmt.Fprintf(t.stdout, "\u001b[1000A\u001b[1000D")
+	o := "\u001b[1000A\u001b[1000D"
 	if x > 0 {
-		fmt.Fprintf(t.stdout, "\u001b["+strconv.Itoa(x)+"C")
+		o += "\u001b[" + strconv.Itoa(x) + "C"
 	}
 	if y > 0 {
-		fmt.Fprintf(t.stdout, "\u001b["+strconv.Itoa(y)+"B")
+		o += "\u001b[" + strconv.Itoa(y) + "B"
 	}
-	fmt.Fprint(t.stdout, s)
+	t.print(o + s)
 }
 
 // Exit closed the program
 func (t *TUI) Exit(i int) {
-	t.clear()
-	cmd := exec.Command("stty", "sane")
-	cmd.Stdin = os.Stdin
-	cmd.Run()
+	t.restoreTTY()
 	os.Exit(i)
 }
+
+// restoreTTY saves layout state and brings the terminal back to its normal
+// state
+func (t *TUI) restoreTTY() {
+	t.saveLayoutState()
+	if t.mouse {
+		t.print("\u001b[?1000l\u001b[?1002l\u001b[?1006l")
+	}
+	t.clear()
+	if t.tty != nil {
+		cmd := exec.Command("stty", "sane")
+		cmd.Stdin = t.tty
+		cmd.Run()
+	}
+	t.noSignals = false
+}
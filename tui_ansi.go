@@ -0,0 +1,221 @@
+package terminalui
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wideRunes are ranges of characters that take two terminal cells
+var wideRunes = [][2]rune{
+	{0x1100, 0x115f}, {0x2e80, 0x303e}, {0x3041, 0x33ff}, {0x3400, 0x4dbf},
+	{0x4e00, 0x9fff}, {0xa000, 0xa4cf}, {0xac00, 0xd7a3}, {0xf900, 0xfaff},
+	{0xfe30, 0xfe4f}, {0xff00, 0xff60}, {0xffe0, 0xffe6}, {0x1f300, 0x1f64f},
+	{0x1f900, 0x1f9ff}, {0x20000, 0x3fffd},
+}
+
+// TUIStyledRun is a part of text which characters have the same style
+type TUIStyledRun struct {
+	Text  string
+	Style TUICellStyle
+}
+
+// GetWidth returns number of terminal cells the text takes
+func (r TUIStyledRun) GetWidth() int {
+	return getStringWidth(r.Text)
+}
+
+// ParseANSI converts single line of text with ANSI escape sequences into
+// runs of characters with the same style. SGR sequences (colors and
+// attributes) set the style, while all the other escape sequences and
+// control characters (including new lines) are removed. Tabs are expanded
+// to spaces.
+func ParseANSI(s string) []TUIStyledRun {
+	runs, _ := parseANSI(s, NewTUICellStyle())
+	return runs
+}
+
+// StripANSI returns text without escape sequences and control characters
+func StripANSI(s string) string {
+	var b strings.Builder
+	for _, r := range ParseANSI(s) {
+		b.WriteString(r.Text)
+	}
+	return b.String()
+}
+
+// GetANSIWidth returns number of terminal cells the text with ANSI escape
+// sequences takes when printed
+func GetANSIWidth(s string) int {
+	n := 0
+	for _, r := range ParseANSI(s) {
+		n += r.GetWidth()
+	}
+	return n
+}
+
+// FitANSI cuts text with ANSI escape sequences to w terminal cells or pads
+// it with spaces, so it is exactly w cells wide. Returned text has only SGR
+// sequences, and style is reset at its end so colors do not bleed into the
+// rest of the terminal window.
+func FitANSI(s string, w int) string {
+	return fitRuns(ParseANSI(s), w)
+}
+
+// WriteANSI prints text with ANSI escape sequences on the pane, cut to the
+// width of pane content so it never overwrites the frame or other panes
+func (p *TUIPane) WriteANSI(x int, y int, s string) {
+	cw := p.GetContentWidth() - x
+	if cw <= 0 || x < 0 || y < 0 || y >= p.GetContentHeight() {
+		return
+	}
+	runs := ParseANSI(s)
+	w := 0
+	for _, r := range runs {
+		w += r.GetWidth()
+	}
+	p.Write(x, y, fitRuns(runs, min(w, cw)), false)
+}
+
+// fitRuns returns runs cut or padded to w cells as text with SGR sequences
+func fitRuns(runs []TUIStyledRun, w int) string {
+	var b strings.Builder
+	cur := NewTUICellStyle()
+	n := 0
+loop:
+	for _, r := range runs {
+		for _, c := range r.Text {
+			cw := getRuneWidth(c)
+			if n+cw > w {
+				break loop
+			}
+			if r.Style != cur {
+				b.WriteString(r.Style.SGR())
+				cur = r.Style
+			}
+			b.WriteRune(c)
+			n += cw
+		}
+	}
+	if !cur.IsDefault() {
+		b.WriteString("\u001b[0m")
+	}
+	if n < w {
+		b.WriteString(strings.Repeat(" ", w-n))
+	}
+	return b.String()
+}
+
+// splitANSILines splits text with ANSI escape sequences into lines. Style
+// set on one line is carried to the next ones (like it is on a terminal) by
+// adding SGR sequence at their beginning.
+func splitANSILines(s string) []string {
+	lines := strings.Split(s, "\n")
+	st := NewTUICellStyle()
+	for i, l := range lines {
+		if !st.IsDefault() {
+			lines[i] = st.SGR() + l
+		}
+		_, st = parseANSI(lines[i], NewTUICellStyle())
+	}
+	return lines
+}
+
+// parseANSI converts text into styled runs starting with style st. It
+// returns the runs and the style at the end of text.
+func parseANSI(s string, st TUICellStyle) ([]TUIStyledRun, TUICellStyle) {
+	var runs []TUIStyledRun
+	col := 0
+	add := func(t string) {
+		if l := len(runs) - 1; l >= 0 && runs[l].Style == st {
+			runs[l].Text += t
+		} else {
+			runs = append(runs, TUIStyledRun{Text: t, Style: st})
+		}
+		col += getStringWidth(t)
+	}
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c == 0x1b {
+			n, params, sgr := parseEscape(s[i:])
+			if sgr {
+				st = st.applySGR(params)
+			}
+			i += n
+			continue
+		}
+		if c == '\t' {
+			add(strings.Repeat(" ", 8-col%8))
+			i++
+			continue
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		i += n
+		if r == utf8.RuneError && n == 1 {
+			r = unicode.ReplacementChar
+		}
+		if unicode.IsControl(r) {
+			continue
+		}
+		add(string(r))
+	}
+	return runs, st
+}
+
+// parseEscape returns length of escape sequence at the beginning of s, and
+// parameters when it is SGR. Sequence that is not complete takes the rest of
+// the text.
+func parseEscape(s string) (int, string, bool) {
+	if len(s) < 2 {
+		return len(s), "", false
+	}
+	switch s[1] {
+	case '[':
+		for j := 2; j < len(s); j++ {
+			if s[j] >= 0x40 && s[j] <= 0x7e {
+				params := s[2:j]
+				sgr := s[j] == 'm' && strings.Trim(params, "0123456789;:") == ""
+				return j + 1, params, sgr
+			}
+		}
+		return len(s), "", false
+	case ']', 'P', '_', '^', 'X':
+		// string ends with BEL or ESC \
+		for j := 2; j < len(s); j++ {
+			if s[j] == 0x07 {
+				return j + 1, "", false
+			}
+			if s[j] == 0x1b && j+1 < len(s) && s[j+1] == '\\' {
+				return j + 2, "", false
+			}
+		}
+		return len(s), "", false
+	case '(', ')', '*', '+':
+		return min(3, len(s)), "", false
+	}
+	return 2, "", false
+}
+
+// getStringWidth returns number of terminal cells the text (without escape
+// sequences) takes
+func getStringWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += getRuneWidth(r)
+	}
+	return n
+}
+
+// getRuneWidth returns number of terminal cells the character takes: 0 for
+// combining marks, 2 for wide East Asian characters and emoji, 1 otherwise
+func getRuneWidth(r rune) int {
+	if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+		return 0
+	}
+	for _, w := range wideRunes {
+		if r >= w[0] && r <= w[1] {
+			return 2
+		}
+	}
+	return 1
+}
@@ -0,0 +1,120 @@
+package terminalui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestApplySGR(t *testing.T) {
+	red := NewTUICellStyle()
+	red.FG = 1
+	tests := []struct {
+		name   string
+		start  TUICellStyle
+		params string
+		want   func(*TUICellStyle)
+	}{
+		{name: "empty resets", start: red, params: "", want: func(s *TUICellStyle) {}},
+		{name: "empty params reset", start: red, params: ";", want: func(s *TUICellStyle) {}},
+		{name: "empty after attribute", params: "1;", want: func(s *TUICellStyle) {}},
+		{name: "attributes", params: "1;3;4;7", want: func(s *TUICellStyle) {
+			s.Bold, s.Italic, s.Underline, s.Reverse = true, true, true, true
+		}},
+		{name: "basic colors", params: "31;42", want: func(s *TUICellStyle) { s.FG, s.BG = 1, 2 }},
+		{name: "bright colors", params: "91;102", want: func(s *TUICellStyle) { s.FG, s.BG = 9, 10 }},
+		{name: "256 colors", params: "38;5;208;48;5;17", want: func(s *TUICellStyle) { s.FG, s.BG = 208, 17 }},
+		{name: "RGB", params: "38;2;255;128;0;1", want: func(s *TUICellStyle) {
+			s.FG, s.Bold = COLOR_RGB|0xff8000, true
+		}},
+		{name: "256 colors sub-parameters", params: "38:5:208;1", want: func(s *TUICellStyle) { s.FG, s.Bold = 208, true }},
+		{name: "RGB sub-parameters with color space", params: "48:2::1:2:3", want: func(s *TUICellStyle) { s.BG = COLOR_RGB | 0x010203 }},
+		{name: "RGB sub-parameters", params: "38:2:1:2:3;4", want: func(s *TUICellStyle) {
+			s.FG, s.Underline = COLOR_RGB|0x010203, true
+		}},
+		{name: "curly underline", params: "4:3", want: func(s *TUICellStyle) { s.Underline = true }},
+		{name: "underline off", start: func() TUICellStyle { s := NewTUICellStyle(); s.Underline = true; return s }(), params: "4:0", want: func(s *TUICellStyle) {}},
+		{name: "underline color ignored", params: "58:2::255:0:0;1", want: func(s *TUICellStyle) { s.Bold = true }},
+		{name: "default colors", start: red, params: "39;49", want: func(s *TUICellStyle) {}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := tt.start
+			if start == (TUICellStyle{}) {
+				start = NewTUICellStyle()
+			}
+			want := NewTUICellStyle()
+			tt.want(&want)
+			if got := start.applySGR(tt.params); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseANSI(t *testing.T) {
+	red := NewTUICellStyle()
+	red.FG = 1
+	tests := []struct {
+		name string
+		s    string
+		want []TUIStyledRun
+	}{
+		{name: "plain", s: "abc", want: []TUIStyledRun{{Text: "abc", Style: NewTUICellStyle()}}},
+		{name: "color", s: "a\u001b[31mb\u001b[;mc", want: []TUIStyledRun{
+			{Text: "a", Style: NewTUICellStyle()}, {Text: "b", Style: red}, {Text: "c", Style: NewTUICellStyle()},
+		}},
+		{name: "tabs", s: "ab\tc\t", want: []TUIStyledRun{{Text: "ab      c       ", Style: NewTUICellStyle()}}},
+		{name: "other sequences removed", s: "a\u001b[2Jb\u001b]0;title\u0007c\u001b(0d\r", want: []TUIStyledRun{{Text: "abcd", Style: NewTUICellStyle()}}},
+		{name: "unterminated CSI", s: "a\u001b[31", want: []TUIStyledRun{{Text: "a", Style: NewTUICellStyle()}}},
+		{name: "unterminated OSC", s: "a\u001b]0;title", want: []TUIStyledRun{{Text: "a", Style: NewTUICellStyle()}}},
+		{name: "OSC ended with ST", s: "a\u001b]8;;url\u001b\\b", want: []TUIStyledRun{{Text: "ab", Style: NewTUICellStyle()}}},
+		{name: "lone escape", s: "a\u001b", want: []TUIStyledRun{{Text: "a", Style: NewTUICellStyle()}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseANSI(tt.s); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFitANSI(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		w    int
+		want string
+	}{
+		{name: "pad", s: "ab", w: 4, want: "ab  "},
+		{name: "cut", s: "abcdef", w: 3, want: "abc"},
+		{name: "style reset", s: "\u001b[1mab", w: 3, want: "\u001b[0;1mab\u001b[0m "},
+		{name: "wide", s: "a世界", w: 5, want: "a世界"},
+		{name: "wide clipped at last column", s: "a世界", w: 4, want: "a世 "},
+		{name: "wide in one column", s: "世", w: 1, want: " "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FitANSI(tt.s, tt.w)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if n := GetANSIWidth(got); n != tt.w {
+				t.Errorf("width is %d, want %d", n, tt.w)
+			}
+		})
+	}
+}
+
+func TestSplitANSILines(t *testing.T) {
+	got := splitANSILines("a\u001b[31mb\nc\u001b[1m\nd\u001b[0m\ne")
+	want := []string{
+		"a\u001b[31mb",
+		"\u001b[0;31mc\u001b[1m",
+		"\u001b[0;1;31md\u001b[0m",
+		"e",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
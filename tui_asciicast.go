@@ -0,0 +1,289 @@
+package terminalui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Codes of asciicast events
+const ASCIICAST_OUTPUT = "o"
+const ASCIICAST_INPUT = "i"
+const ASCIICAST_RESIZE = "r"
+
+// TUIAsciicast is a recording in asciicast v2 format: header with terminal
+// size and a list of timed events (output, input and resize).
+type TUIAsciicast struct {
+	Width     int                  `json:"width"`
+	Height    int                  `json:"height"`
+	Timestamp int64                `json:"timestamp,omitempty"`
+	Env       map[string]string    `json:"env,omitempty"`
+	Events    []*TUIAsciicastEvent `json:"-"`
+}
+
+// TUIAsciicastEvent is a single event of asciicast. Time is number of
+// seconds since the recording started. Data of resize event is "WxH".
+type TUIAsciicastEvent struct {
+	Time float64
+	Type string
+	Data string
+}
+
+// GetDuration returns time of the last event in seconds
+func (c *TUIAsciicast) GetDuration() float64 {
+	if len(c.Events) == 0 {
+		return 0
+	}
+	return c.Events[len(c.Events)-1].Time
+}
+
+// Write writes asciicast to w in asciicast v2 format
+func (c *TUIAsciicast) Write(w io.Writer) error {
+	err := writeAsciicastHeader(w, c)
+	if err != nil {
+		return err
+	}
+	for _, e := range c.Events {
+		err = writeAsciicastEvent(w, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadAsciicast reads asciicast v2 from r
+func ReadAsciicast(r io.Reader) (*TUIAsciicast, error) {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	if !s.Scan() {
+		if s.Err() != nil {
+			return nil, s.Err()
+		}
+		return nil, errors.New("asciicast header is missing")
+	}
+	var h struct {
+		Version int `json:"version"`
+		TUIAsciicast
+	}
+	err := json.Unmarshal(s.Bytes(), &h)
+	if err != nil {
+		return nil, err
+	}
+	if h.Version != 2 {
+		return nil, errors.New("unsupported asciicast version " + strconv.Itoa(h.Version))
+	}
+	c := &h.TUIAsciicast
+	for n := 2; s.Scan(); n++ {
+		l := strings.TrimSpace(s.Text())
+		if l == "" {
+			continue
+		}
+		var e []interface{}
+		err = json.Unmarshal([]byte(l), &e)
+		if err != nil {
+			return nil, errors.New("invalid asciicast event on line " + strconv.Itoa(n) + ": " + err.Error())
+		}
+		if len(e) != 3 {
+			return nil, errors.New("invalid asciicast event on line " + strconv.Itoa(n))
+		}
+		tm, ok1 := e[0].(float64)
+		typ, ok2 := e[1].(string)
+		data, ok3 := e[2].(string)
+		if !ok1 || !ok2 || !ok3 {
+			return nil, errors.New("invalid asciicast event on line " + strconv.Itoa(n))
+		}
+		c.Events = append(c.Events, &TUIAsciicastEvent{Time: tm, Type: typ, Data: data})
+	}
+	return c, s.Err()
+}
+
+// NewTUIAsciicast returns new empty instance of TUIAsciicast of specific
+// terminal size
+func NewTUIAsciicast(w int, h int) *TUIAsciicast {
+	return &TUIAsciicast{Width: w, Height: h, Timestamp: time.Now().Unix(), Env: map[string]string{}}
+}
+
+// writeAsciicastHeader writes header line of asciicast
+func writeAsciicastHeader(w io.Writer, c *TUIAsciicast) error {
+	b, err := json.Marshal(struct {
+		Version int `json:"version"`
+		*TUIAsciicast
+	}{2, c})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// writeAsciicastEvent writes event line of asciicast
+func writeAsciicastEvent(w io.Writer, e *TUIAsciicastEvent) error {
+	b, err := json.Marshal([]interface{}{json.Number(strconv.FormatFloat(e.Time, 'f', 6, 64)), e.Type, e.Data})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
+
+// tuiRecorder writes everything TUI outputs (and optionally its input) to
+// asciicast stream. Events are written on the output path (with stdoutMu
+// locked), so a slow writer slows down the output. They are buffered to
+// write in bigger chunks, and flushed when recording stops or Run returns.
+type tuiRecorder struct {
+	w     *bufio.Writer
+	start time.Time
+	input bool
+	err   error
+}
+
+// add writes event to the stream. After first error it stops writing.
+func (r *tuiRecorder) add(typ string, data string) {
+	if r.err != nil {
+		return
+	}
+	r.err = writeAsciicastEvent(r.w, &TUIAsciicastEvent{Time: time.Since(r.start).Seconds(), Type: typ, Data: data})
+}
+
+// flush writes buffered events to the stream
+func (r *tuiRecorder) flush() {
+	if r.err != nil {
+		return
+	}
+	r.err = r.w.Flush()
+}
+
+// StartRecording starts writing everything the interface outputs, with
+// timestamps and terminal resizes, to w in asciicast v2 format. When input
+// is true, keyboard input is recorded as well so it can be passed to Replay
+// later. Interface is redrawn so the recording starts with full screen. It
+// can be called before Run. Events are buffered, and the rest of them is
+// written out when StopRecording is called or Run returns. Writing to w
+// blocks the output, so w should be fast (eg. a file).
+func (t *TUI) StartRecording(w io.Writer, input bool) error {
+	c := NewTUIAsciicast(t.w, t.h)
+	if c.Width <= 0 || c.Height <= 0 {
+		c.Width, c.Height, _ = t.getSize()
+	}
+	if c.Width <= 0 || c.Height <= 0 {
+		c.Width, c.Height = 80, 24
+	}
+	if term := os.Getenv("TERM"); term != "" {
+		c.Env["TERM"] = term
+	}
+	err := writeAsciicastHeader(w, c)
+	if err != nil {
+		return err
+	}
+	t.stdoutMu.Lock()
+	t.recorder = &tuiRecorder{w: bufio.NewWriter(w), start: time.Now(), input: input}
+	t.stdoutMu.Unlock()
+	t.Refresh()
+	return nil
+}
+
+// StopRecording stops recording and returns first error that occurred while
+// writing the events
+func (t *TUI) StopRecording() error {
+	t.stdoutMu.Lock()
+	defer t.stdoutMu.Unlock()
+	if t.recorder == nil {
+		return nil
+	}
+	t.recorder.flush()
+	err := t.recorder.err
+	t.recorder = nil
+	return err
+}
+
+// flushRecording writes buffered events of the recording if there is one
+func (t *TUI) flushRecording() {
+	t.stdoutMu.Lock()
+	defer t.stdoutMu.Unlock()
+	if t.recorder != nil {
+		t.recorder.flush()
+	}
+}
+
+// IsRecording returns true when interface is being recorded
+func (t *TUI) IsRecording() bool {
+	t.stdoutMu.Lock()
+	defer t.stdoutMu.Unlock()
+	return t.recorder != nil
+}
+
+// record adds event to the recording if there is one. Input events are
+// added only when recording of input was requested.
+func (t *TUI) record(typ string, data string) {
+	t.stdoutMu.Lock()
+	defer t.stdoutMu.Unlock()
+	t.recordLocked(typ, data)
+}
+
+// recordLocked is record that is called with stdoutMu already held
+func (t *TUI) recordLocked(typ string, data string) {
+	if t.recorder == nil || (typ == ASCIICAST_INPUT && !t.recorder.input) {
+		return
+	}
+	t.recorder.add(typ, data)
+}
+
+// Replay feeds input events of the asciicast into running interface as if
+// the keys were pressed, keeping the recorded timing divided by speed (0
+// means no waiting). Resize events change the size set with SetSize, so they
+// have effect only when interface has no tty. It blocks until all the
+// events are passed or interface stops, and should be called from a
+// goroutine other than the UI one.
+func (t *TUI) Replay(c *TUIAsciicast, speed float64) error {
+	if !t.isRunning() {
+		return errors.New("interface is not running")
+	}
+	start := time.Now()
+	for _, e := range c.Events {
+		if e.Type != ASCIICAST_INPUT && e.Type != ASCIICAST_RESIZE {
+			continue
+		}
+		if speed > 0 {
+			d := time.Duration(e.Time / speed * float64(time.Second))
+			time.Sleep(time.Until(start.Add(d)))
+		}
+		if !t.isRunning() {
+			return errors.New("interface stopped during replay")
+		}
+		switch e.Type {
+		case ASCIICAST_INPUT:
+			keys, _ := splitKeys([]byte(e.Data), false)
+			t.Post(func() {
+				for _, k := range keys {
+					t.input(k)
+				}
+			})
+		case ASCIICAST_RESIZE:
+			w, h, ok := parseAsciicastSize(e.Data)
+			if ok {
+				t.SetSize(w, h)
+			}
+		}
+	}
+	return nil
+}
+
+// parseAsciicastSize parses data of resize event ("WxH")
+func parseAsciicastSize(s string) (int, int, bool) {
+	ws, hs, ok := strings.Cut(s, "x")
+	if !ok {
+		return 0, 0, false
+	}
+	w, err1 := strconv.Atoi(ws)
+	h, err2 := strconv.Atoi(hs)
+	if err1 != nil || err2 != nil || w <= 0 || h <= 0 {
+		return 0, 0, false
+	}
+	return w, h, true
+}
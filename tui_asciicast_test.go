@@ -0,0 +1,85 @@
+package terminalui
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAsciicastRoundTrip(t *testing.T) {
+	c := NewTUIAsciicast(80, 24)
+	c.Env["TERM"] = "xterm"
+	c.Events = []*TUIAsciicastEvent{
+		{Time: 0, Type: ASCIICAST_OUTPUT, Data: "\u001b[2J\u001b[1;1Hhello"},
+		{Time: 0.5, Type: ASCIICAST_INPUT, Data: "q\u001b[A"},
+		{Time: 1.25, Type: ASCIICAST_RESIZE, Data: "100x30"},
+		{Time: 2, Type: ASCIICAST_OUTPUT, Data: "zażółć \"quoted\"\n"},
+	}
+	var b bytes.Buffer
+	if err := c.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadAsciicast(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, c) {
+		t.Errorf("got %+v, want %+v", got, c)
+	}
+	if got.GetDuration() != 2 {
+		t.Errorf("duration is %v, want 2", got.GetDuration())
+	}
+}
+
+func TestReadAsciicastInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		s    string
+	}{
+		{name: "empty", s: ""},
+		{name: "version", s: `{"version": 1, "width": 80, "height": 24}`},
+		{name: "header", s: `[2, "o", "x"]`},
+		{name: "event json", s: "{\"version\": 2}\n[0.1, \"o\""},
+		{name: "event length", s: "{\"version\": 2}\n[0.1, \"o\"]"},
+		{name: "event types", s: "{\"version\": 2}\n[\"0.1\", \"o\", \"x\"]"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadAsciicast(strings.NewReader(tt.s)); err == nil {
+				t.Error("no error")
+			}
+		})
+	}
+}
+
+func TestRecording(t *testing.T) {
+	tui := NewTUI()
+	tui.SetOutput(io.Discard)
+	tui.SetSize(20, 5)
+	var b bytes.Buffer
+	if err := tui.StartRecording(&b, true); err != nil {
+		t.Fatal(err)
+	}
+	tui.print("hello")
+	tui.record(ASCIICAST_INPUT, "q")
+	if strings.Count(b.String(), "\n") != 1 {
+		t.Errorf("events were written before recording stopped: %q", b.String())
+	}
+	if err := tui.StopRecording(); err != nil {
+		t.Fatal(err)
+	}
+	if tui.IsRecording() {
+		t.Error("still recording")
+	}
+	c, err := ReadAsciicast(&b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Width != 20 || c.Height != 5 {
+		t.Errorf("size is %dx%d, want 20x5", c.Width, c.Height)
+	}
+	if len(c.Events) != 2 || c.Events[0].Data != "hello" || c.Events[1].Type != ASCIICAST_INPUT {
+		t.Errorf("got events %+v", c.Events)
+	}
+}
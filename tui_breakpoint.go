@@ -0,0 +1,64 @@
+package terminalui
+
+// tuiBreakpoint is an alternative layout used when terminal window is not
+// larger than specific size
+type tuiBreakpoint struct {
+	maxWidth  int
+	maxHeight int
+	layout    *TUILayout
+}
+
+// AddBreakpoint registers an alternative layout that is used when terminal
+// width is not larger than maxWidth and height is not larger than maxHeight
+// (0 means any). Breakpoints are checked in the order they were added and
+// the first matching one is used, so the smallest ones should go first.
+// When the size changes and layout is switched, panes are matched by names
+// so they keep their widgets. Panes that are missing in the layout are
+// hidden until the layout that has them is used again.
+func (t *TUI) AddBreakpoint(maxWidth int, maxHeight int, l *TUILayout) {
+	t.breakpoints = append(t.breakpoints, &tuiBreakpoint{maxWidth: maxWidth, maxHeight: maxHeight, layout: l})
+}
+
+// getBreakpoint returns breakpoint matching current terminal size or nil
+func (t *TUI) getBreakpoint() *tuiBreakpoint {
+	for _, b := range t.breakpoints {
+		if (b.maxWidth == 0 || t.w <= b.maxWidth) && (b.maxHeight == 0 || t.h <= b.maxHeight) {
+			return b
+		}
+	}
+	return nil
+}
+
+// applyBreakpoint switches layout when terminal size matches another
+// breakpoint. Layout from before the first switch is used when no
+// breakpoint matches.
+func (t *TUI) applyBreakpoint() {
+	b := t.getBreakpoint()
+	if b == t.breakpoint {
+		return
+	}
+	if t.breakpoint == nil {
+		t.defaultLayout = t.pane.GetLayout()
+	}
+	if t.breakpointPanes == nil {
+		t.breakpointPanes = map[string]*TUIPane{}
+	}
+	for n, p := range t.getPanesByName() {
+		t.breakpointPanes[n] = p
+	}
+
+	l := t.defaultLayout
+	if b != nil {
+		l = b.layout
+	}
+	t.pane = t.arrangePanes(l, t.breakpointPanes)
+	t.pane.parent = nil
+	t.breakpoint = b
+
+	if t.focus != nil && t.focus != t.pane && !t.pane.isAncestorOf(t.focus) {
+		t.focus = nil
+	}
+	if t.zoomed != nil && (t.zoomed == t.pane || !t.pane.isAncestorOf(t.zoomed)) {
+		t.zoomed = nil
+	}
+}
@@ -0,0 +1,91 @@
+package terminalui
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Formats of screen capture
+const CAPTURE_TEXT = 1
+const CAPTURE_HTML = 2
+const CAPTURE_SVG = 3
+
+// GetScreen returns screen that keeps what the interface has drawn on the
+// terminal window: all the panes, borders, overlays and colors. It is
+// updated with everything that is written to the output. Screen is kept
+// only after it is requested for the first time (by GetScreen, Capture or
+// BindCaptureKey), and then the interface is redrawn to fill it, so call it
+// before Run to have the screen from the start.
+func (t *TUI) GetScreen() *TUIScreen {
+	t.stdoutMu.Lock()
+	s := t.screen
+	if s != nil {
+		t.stdoutMu.Unlock()
+		return s
+	}
+	s = NewTUIScreen(t.w, t.h)
+	t.screen = s
+	t.stdoutMu.Unlock()
+	t.Refresh()
+	return s
+}
+
+// Capture writes what is currently drawn on the terminal window to w in
+// one of the formats: CAPTURE_TEXT, CAPTURE_HTML or CAPTURE_SVG. When the
+// screen was not kept before (see GetScreen), the first capture of running
+// interface is empty.
+func (t *TUI) Capture(w io.Writer, format int) error {
+	s := t.GetScreen()
+	switch format {
+	case CAPTURE_TEXT:
+		return s.WriteText(w)
+	case CAPTURE_HTML:
+		return s.WriteHTML(w)
+	case CAPTURE_SVG:
+		return s.WriteSVG(w)
+	}
+	return errors.New("invalid capture format")
+}
+
+// SaveCapture writes what is currently drawn on the terminal window to a
+// file. Format depends on the file extension: .txt, .html (or .htm) or
+// .svg. Text "{time}" in the path is replaced with current date and time.
+// It returns path of the file.
+func (t *TUI) SaveCapture(path string) (string, error) {
+	path = strings.ReplaceAll(path, "{time}", time.Now().Format("20060102-150405"))
+	formats := map[string]int{
+		".txt": CAPTURE_TEXT, ".html": CAPTURE_HTML, ".htm": CAPTURE_HTML, ".svg": CAPTURE_SVG,
+	}
+	format, ok := formats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return path, errors.New("unknown capture format of '" + path + "'")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return path, err
+	}
+	err = t.Capture(f, format)
+	if err != nil {
+		f.Close()
+		return path, err
+	}
+	return path, f.Close()
+}
+
+// BindCaptureKey makes key save the screen capture to a file (see
+// SaveCapture). Function f, if not nil, is called afterwards with path of
+// the file and an error if saving failed. Screen is kept from now on (see
+// GetScreen).
+func (t *TUI) BindCaptureKey(k string, path string, f func(*TUI, string, error)) {
+	t.GetScreen()
+	t.BindKey(k, func(t *TUI) {
+		p, err := t.SaveCapture(path)
+		if f != nil {
+			f(t, p, err)
+		}
+	})
+}
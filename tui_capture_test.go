@@ -0,0 +1,35 @@
+package terminalui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCaptureEnablesScreen(t *testing.T) {
+	tui := NewTUI()
+	tui.SetOutput(io.Discard)
+	tui.SetSize(10, 2)
+	tui.refreshSize()
+	tui.print("before")
+	if tui.screen != nil {
+		t.Fatal("screen is kept before it was requested")
+	}
+
+	var b bytes.Buffer
+	if err := tui.Capture(&b, CAPTURE_TEXT); err != nil {
+		t.Fatal(err)
+	}
+	tui.print("\u001b[1;1Hafter")
+	b.Reset()
+	if err := tui.Capture(&b, CAPTURE_TEXT); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), "after") {
+		t.Errorf("got %q", b.String())
+	}
+	if err := tui.Capture(&b, 0); err == nil {
+		t.Error("no error for invalid format")
+	}
+}
@@ -0,0 +1,180 @@
+package terminalui
+
+import (
+	"strconv"
+	"strings"
+)
+
+const COLOR_DEFAULT = -1
+const COLOR_RGB = 0x1000000
+
+// TUICellStyle describes how a character cell looks: its colors and
+// attributes. Colors are either COLOR_DEFAULT, a number from 256 color
+// palette, or 24-bit RGB value with COLOR_RGB flag (eg. COLOR_RGB|0xff0000).
+type TUICellStyle struct {
+	FG        int
+	BG        int
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+	Blink     bool
+	Reverse   bool
+	Strike    bool
+}
+
+// IsDefault returns true when style has default colors and no attributes
+func (s TUICellStyle) IsDefault() bool {
+	return s == NewTUICellStyle()
+}
+
+// SGR returns escape sequence that resets terminal attributes and sets the
+// ones of the style
+func (s TUICellStyle) SGR() string {
+	p := []string{"0"}
+	flags := []struct {
+		on bool
+		p  string
+	}{
+		{s.Bold, "1"}, {s.Dim, "2"}, {s.Italic, "3"}, {s.Underline, "4"},
+		{s.Blink, "5"}, {s.Reverse, "7"}, {s.Strike, "9"},
+	}
+	for _, f := range flags {
+		if f.on {
+			p = append(p, f.p)
+		}
+	}
+	p = append(p, colorSGR(s.FG, 30)...)
+	p = append(p, colorSGR(s.BG, 40)...)
+	return "\u001b[" + strings.Join(p, ";") + "m"
+}
+
+// colorSGR returns SGR parameters for foreground (base 30) or background
+// (base 40) color
+func colorSGR(c int, base int) []string {
+	switch {
+	case c == COLOR_DEFAULT:
+		return nil
+	case c&COLOR_RGB != 0:
+		return []string{strconv.Itoa(base + 8), "2", strconv.Itoa(c >> 16 & 0xff), strconv.Itoa(c >> 8 & 0xff), strconv.Itoa(c & 0xff)}
+	case c < 8:
+		return []string{strconv.Itoa(base + c)}
+	case c < 16:
+		return []string{strconv.Itoa(base + 60 + c - 8)}
+	}
+	return []string{strconv.Itoa(base + 8), "5", strconv.Itoa(c)}
+}
+
+// applySGR returns style changed by parameters of SGR escape sequence (the
+// part between "\u001b[" and "m"). Parameters are separated with ";" and
+// each of them can have sub-parameters separated with ":" (eg.
+// "38:2::255:0:0"). Empty parameters are 0. Unknown parameters are ignored.
+func (s TUICellStyle) applySGR(params string) TUICellStyle {
+	var ps [][]int
+	for _, g := range strings.Split(params, ";") {
+		var sub []int
+		for _, x := range strings.Split(g, ":") {
+			i, err := strconv.Atoi(x)
+			if err != nil {
+				i = 0
+			}
+			sub = append(sub, i)
+		}
+		ps = append(ps, sub)
+	}
+	for i := 0; i < len(ps); i++ {
+		switch p := ps[i][0]; {
+		case p == 0:
+			s = NewTUICellStyle()
+		case p == 1:
+			s.Bold = true
+		case p == 2:
+			s.Dim = true
+		case p == 3:
+			s.Italic = true
+		case p == 4:
+			// "4:0" turns underline off, other styles (eg. "4:3" curly) are
+			// shown as a plain underline
+			s.Underline = len(ps[i]) < 2 || ps[i][1] != 0
+		case p == 5 || p == 6:
+			s.Blink = true
+		case p == 7:
+			s.Reverse = true
+		case p == 9:
+			s.Strike = true
+		case p == 21 || p == 22:
+			s.Bold, s.Dim = false, false
+		case p == 23:
+			s.Italic = false
+		case p == 24:
+			s.Underline = false
+		case p == 25:
+			s.Blink = false
+		case p == 27:
+			s.Reverse = false
+		case p == 29:
+			s.Strike = false
+		case p >= 30 && p <= 37:
+			s.FG = p - 30
+		case p == 38 || p == 48 || p == 58:
+			var c int
+			if len(ps[i]) > 1 {
+				c = parseSubColor(ps[i][1:])
+			} else {
+				var rest []int
+				for _, g := range ps[i+1:] {
+					rest = append(rest, g[0])
+				}
+				var n int
+				c, n = parseExtendedColor(rest)
+				i += n
+			}
+			// underline color (58) is not supported
+			if p == 38 {
+				s.FG = c
+			} else if p == 48 {
+				s.BG = c
+			}
+		case p == 39:
+			s.FG = COLOR_DEFAULT
+		case p >= 40 && p <= 47:
+			s.BG = p - 40
+		case p == 49:
+			s.BG = COLOR_DEFAULT
+		case p >= 90 && p <= 97:
+			s.FG = p - 90 + 8
+		case p >= 100 && p <= 107:
+			s.BG = p - 100 + 8
+		}
+	}
+	return s
+}
+
+// parseExtendedColor parses parameters following 38 or 48 in SGR: "5;n" for
+// palette color or "2;r;g;b" for RGB. It returns the color and number of
+// parameters used.
+func parseExtendedColor(ps []int) (int, int) {
+	if len(ps) >= 2 && ps[0] == 5 {
+		return ps[1] & 0xff, 2
+	}
+	if len(ps) >= 4 && ps[0] == 2 {
+		return COLOR_RGB | (ps[1]&0xff)<<16 | (ps[2]&0xff)<<8 | ps[3]&0xff, 4
+	}
+	return COLOR_DEFAULT, len(ps)
+}
+
+// parseSubColor parses sub-parameters following 38 or 48 in SGR: "5:n" for
+// palette color, and "2:id:r:g:b" (with color space id, usually empty) or
+// "2:r:g:b" for RGB
+func parseSubColor(ps []int) int {
+	if len(ps) == 5 && ps[0] == 2 {
+		ps = append([]int{2}, ps[2:]...)
+	}
+	c, _ := parseExtendedColor(ps)
+	return c
+}
+
+// NewTUICellStyle returns style with default colors and no attributes
+func NewTUICellStyle() TUICellStyle {
+	return TUICellStyle{FG: COLOR_DEFAULT, BG: COLOR_DEFAULT}
+}
@@ -0,0 +1,225 @@
+package terminalui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/go-phings/terminal-ui/internal/socket"
+)
+
+// ListenControl starts listening on Unix socket at path for commands that
+// control the running interface (eg. from shell scripts). Each line sent to
+// the socket is a command and the response is a line starting with "ok"
+// (followed by the result) or "error" (followed by the message). Commands
+// are:
+//
+//	panes                                   names of all the panes
+//	focus NAME                              sets focus on the pane
+//	split NAME vertical|horizontal [SIZE]   splits pane (SIZE is eg. 30%, -20ch) and returns names of new panes
+//	close NAME                              closes the pane
+//	zoom NAME                               zooms the pane
+//	unzoom                                  restores the layout after zoom
+//	style NAME frame|margin|none            sets style of the pane
+//	log NAME TEXT                           adds line of text to the log widget in the pane
+//	layout                                  returns layout in JSON format
+//	quit [CODE]                             stops the interface
+//
+// Socket can be used only by the user that runs the interface. Socket left by
+// a process that has not stopped cleanly is removed, but an error is returned
+// when another process is listening on it. Socket is removed when Run
+// returns.
+func (t *TUI) ListenControl(path string) error {
+	l, err := socket.Listen(path)
+	if err != nil {
+		return err
+	}
+	t.controlMu.Lock()
+	t.control = l
+	t.controlConns = map[net.Conn]bool{}
+	t.controlMu.Unlock()
+	go t.acceptControl(l)
+	return nil
+}
+
+// CloseControl stops listening for commands, closes connections and removes
+// the socket
+func (t *TUI) CloseControl() error {
+	t.controlMu.Lock()
+	l := t.control
+	conns := t.controlConns
+	t.control = nil
+	t.controlConns = nil
+	t.controlMu.Unlock()
+	if l == nil {
+		return nil
+	}
+	for c := range conns {
+		c.Close()
+	}
+	err := l.Close()
+	os.Remove(l.Addr().String())
+	return err
+}
+
+// acceptControl accepts connections until listener is closed
+func (t *TUI) acceptControl(l net.Listener) {
+	for {
+		c, err := l.Accept()
+		if err != nil {
+			return
+		}
+		go t.handleControl(c)
+	}
+}
+
+// handleControl reads commands, runs each of them on the UI goroutine and
+// writes back the results
+func (t *TUI) handleControl(c net.Conn) {
+	defer c.Close()
+	t.controlMu.Lock()
+	if t.controlConns == nil {
+		t.controlMu.Unlock()
+		return
+	}
+	t.controlConns[c] = true
+	t.controlMu.Unlock()
+	defer func() {
+		t.controlMu.Lock()
+		delete(t.controlConns, c)
+		t.controlMu.Unlock()
+	}()
+
+	s := bufio.NewScanner(c)
+	for s.Scan() {
+		l := strings.TrimSpace(s.Text())
+		if l == "" {
+			continue
+		}
+		var r string
+		var err error
+		perr := t.PostAndWait(func() {
+			r, err = t.runCommand(l)
+		})
+		if perr != nil {
+			err = perr
+		}
+		if err != nil {
+			r = "error " + err.Error()
+		} else if r != "" {
+			r = "ok " + r
+		} else {
+			r = "ok"
+		}
+		_, err = c.Write([]byte(r + "\n"))
+		if err != nil {
+			return
+		}
+	}
+}
+
+// runCommand runs control command and returns its result
+func (t *TUI) runCommand(l string) (string, error) {
+	args := strings.Fields(l)
+	argc := map[string]int{
+		"panes": 0, "focus": 1, "split": 2, "close": 1, "zoom": 1, "unzoom": 0,
+		"style": 2, "log": 2, "layout": 0, "quit": 0,
+	}
+	n, ok := argc[args[0]]
+	if !ok {
+		return "", errors.New("unknown command '" + args[0] + "'")
+	}
+	if len(args)-1 < n {
+		return "", errors.New("command '" + args[0] + "' needs " + strconv.Itoa(n) + " arguments")
+	}
+	var p *TUIPane
+	if n > 0 && args[0] != "quit" {
+		p = t.GetPaneByName(args[1])
+		if p == nil {
+			return "", errors.New("pane '" + args[1] + "' not found")
+		}
+	}
+
+	switch args[0] {
+	case "panes":
+		var names []string
+		t.Walk(func(p *TUIPane, d int) bool {
+			names = append(names, p.name)
+			return true
+		})
+		return strings.Join(names, " "), nil
+	case "focus":
+		t.SetFocus(p)
+	case "split":
+		split, ok := layoutSplits[args[2]]
+		if !ok || (split != SPLIT_V && split != SPLIT_H) {
+			return "", errors.New("invalid split '" + args[2] + "'")
+		}
+		s, u := 50, UNIT_PERCENT
+		if len(args) > 3 {
+			var err error
+			s, u, err = parseSize(args[3])
+			if err != nil {
+				return "", err
+			}
+		}
+		// split is done right away to return final names of the new panes
+		c0, c1, f := p.prepareSplit(split, s, u)
+		f()
+		t.Refresh()
+		return c0.name + " " + c1.name, nil
+	case "close":
+		return "", t.ClosePane(p)
+	case "zoom":
+		t.Zoom(p)
+	case "unzoom":
+		t.Unzoom()
+	case "style":
+		f, ok := layoutStyles[args[2]]
+		if !ok {
+			return "", errors.New("invalid style '" + args[2] + "'")
+		}
+		p.SetStyle(f())
+		t.Refresh()
+	case "log":
+		if p.log == nil {
+			return "", errors.New("pane '" + p.name + "' has no log widget")
+		}
+		// text is everything after the pane name, with spaces kept
+		s := strings.TrimSpace(l[len(args[0]):])
+		p.log.Add(strings.TrimSpace(s[len(args[1]):]))
+	case "layout":
+		b, err := json.Marshal(t.pane.GetLayout())
+		return string(b), err
+	case "quit":
+		code := 0
+		if len(args) > 1 {
+			var err error
+			code, err = strconv.Atoi(args[1])
+			if err != nil {
+				return "", errors.New("invalid code '" + args[1] + "'")
+			}
+		}
+		t.quit(code)
+	}
+	return "", nil
+}
+
+// parseSize parses split size with unit, eg. "30%" or "-20ch"
+func parseSize(s string) (int, int, error) {
+	u := UNIT_PERCENT
+	v := strings.TrimSuffix(s, "%")
+	if strings.HasSuffix(s, "ch") {
+		u = UNIT_CHAR
+		v = strings.TrimSuffix(s, "ch")
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i == 0 {
+		return 0, 0, errors.New("invalid size '" + s + "'")
+	}
+	return i, u, nil
+}
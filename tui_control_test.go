@@ -0,0 +1,179 @@
+package terminalui
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRunCommand(t *testing.T) {
+	tui := NewTUI()
+	for _, tt := range []struct {
+		cmd  string
+		want string
+		err  bool
+	}{
+		{cmd: "panes", want: "main"},
+		{cmd: "split main vertical 30%", want: "main.0 main.1"},
+		{cmd: "split main.1 horizontal -5ch", want: "main.1.0 main.1.1"},
+		{cmd: "panes", want: "main main.0 main.1 main.1.0 main.1.1"},
+		{cmd: "focus main.0"},
+		{cmd: "style main.0 frame"},
+		{cmd: "zoom main.1.0"},
+		{cmd: "unzoom"},
+		{cmd: "close main.1.1"},
+		{cmd: "panes", want: "main main.0 main.1.0"},
+		{cmd: "unknown", err: true},
+		{cmd: "focus", err: true},
+		{cmd: "focus missing", err: true},
+		{cmd: "split main diagonal", err: true},
+		{cmd: "split main vertical 0", err: true},
+		{cmd: "style main.0 fancy", err: true},
+		{cmd: "log main.0 text", err: true},
+		{cmd: "quit x", err: true},
+	} {
+		got, err := tui.runCommand(tt.cmd)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error %v", tt.cmd, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.cmd, got, tt.want)
+		}
+	}
+	if tui.GetFocus() != tui.GetPaneByName("main.0") {
+		t.Errorf("focus was not set")
+	}
+
+	w := NewTUIWidgetLog(10)
+	w.InitPane(tui.GetPaneByName("main.0"))
+	if _, err := tui.runCommand("log main.0  two words"); err != nil {
+		t.Fatal(err)
+	}
+	if l := w.GetLines(); len(l) != 1 || l[0] != "two words" {
+		t.Errorf("log has %q", l)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		v    int
+		unit int
+		err  bool
+	}{
+		{s: "30%", v: 30, unit: UNIT_PERCENT},
+		{s: "-30", v: -30, unit: UNIT_PERCENT},
+		{s: "20ch", v: 20, unit: UNIT_CHAR},
+		{s: "-20ch", v: -20, unit: UNIT_CHAR},
+		{s: "0", err: true},
+		{s: "0ch", err: true},
+		{s: "ch", err: true},
+		{s: "x%", err: true},
+	} {
+		v, u, err := parseSize(tt.s)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error %v", tt.s, err)
+			continue
+		}
+		if !tt.err && (v != tt.v || u != tt.unit) {
+			t.Errorf("%s: got %d %d, want %d %d", tt.s, v, u, tt.v, tt.unit)
+		}
+	}
+}
+
+func TestCloseControl(t *testing.T) {
+	tui := NewTUI()
+	path := filepath.Join(t.TempDir(), "c.sock")
+	if err := tui.ListenControl(path); err != nil {
+		t.Fatal(err)
+	}
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	r := bufio.NewReader(c)
+	c.Write([]byte("panes\n"))
+	if l, _ := r.ReadString('\n'); l != "ok main\n" {
+		t.Errorf("got %q", l)
+	}
+
+	tui.CloseControl()
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, err := r.ReadString('\n'); err != io.EOF {
+		t.Errorf("connection was not closed: %v", err)
+	}
+}
+
+func TestListenControlSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "c.sock")
+	// socket left by a process that was killed
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.(*net.UnixListener).SetUnlinkOnClose(false)
+	l.Close()
+
+	tui := NewTUI()
+	if err := tui.ListenControl(path); err != nil {
+		t.Fatal(err)
+	}
+	defer tui.CloseControl()
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Errorf("socket mode is %v, want 0600", fi.Mode().Perm())
+	}
+	if err := NewTUI().ListenControl(path); err == nil {
+		t.Error("socket that is listened on was replaced")
+	}
+
+	file := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(file, []byte("data"), 0600)
+	if err := NewTUI().ListenControl(file); err == nil {
+		t.Error("file that is not a socket was replaced")
+	}
+}
+
+func TestPostAndWaitAfterStop(t *testing.T) {
+	tui := NewTUI()
+	tui.done = make(chan bool)
+	tui.setRunning(true)
+	errc := make(chan error)
+	go func() {
+		errc <- tui.PostAndWait(func() {})
+	}()
+	for {
+		tui.eventsMu.Lock()
+		n := len(tui.events)
+		tui.eventsMu.Unlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// main loop stops without calling the posted function
+	tui.eventsMu.Lock()
+	tui.stopped = true
+	tui.eventsMu.Unlock()
+	close(tui.done)
+	select {
+	case err := <-errc:
+		if err == nil {
+			t.Error("no error when loop stopped")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("PostAndWait did not return")
+	}
+	if err := tui.PostAndWait(func() {}); err == nil {
+		t.Error("no error when posting after loop stopped")
+	}
+}
@@ -0,0 +1,171 @@
+package terminalui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tuiDialog is a widget drawn inside modal overlay opened by Alert, Confirm
+// and Prompt
+type tuiDialog struct {
+	title    string
+	lines    []string
+	buttons  []string
+	selected int
+	prompt   bool
+	value    []rune
+	onClose  func(button int, value string)
+}
+
+// draw prints out dialog message, input and buttons
+func (d *tuiDialog) draw(p *TUIPane) int {
+	cw := p.GetContentWidth()
+	if t := []rune(" " + d.title + " "); d.title != "" && p.GetWidth() > 4 {
+		if len(t) > p.GetWidth()-2 {
+			t = t[:p.GetWidth()-2]
+		}
+		p.Write(1, 0, string(t), true)
+	}
+	for i, l := range d.lines {
+		p.Write(1, i, fitString(l, cw-2), false)
+	}
+	if d.prompt {
+		v := string(d.value)
+		if r := []rune(v); len(r) > cw-3 {
+			v = string(r[len(r)-cw+3:])
+		}
+		p.Write(1, len(d.lines)+1, "\u001b[4m"+fitString(v, cw-2)+"\u001b[0m", false)
+	}
+	x := cw
+	for i := len(d.buttons) - 1; i >= 0; i-- {
+		b := "[ " + d.buttons[i] + " ]"
+		x -= len([]rune(b)) + 1
+		if i == d.selected {
+			b = "\u001b[7m" + b + "\u001b[0m"
+		}
+		p.Write(x, p.GetContentHeight()-1, b, false)
+	}
+	return 1
+}
+
+// keyPress handles keys pressed while the dialog is open
+func (d *tuiDialog) keyPress(o *TUIOverlay, b []byte) {
+	switch string(b) {
+	case KEY_LEFT:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case KEY_RIGHT, KEY_TAB:
+		d.selected = (d.selected + 1) % len(d.buttons)
+	case KEY_ENTER:
+		o.Close()
+		d.onClose(d.selected, string(d.value))
+		return
+	case KEY_ESC:
+		o.Close()
+		d.onClose(-1, string(d.value))
+		return
+	case KEY_BACKSPACE:
+		if d.prompt && len(d.value) > 0 {
+			d.value = d.value[:len(d.value)-1]
+		}
+	default:
+		if !d.prompt {
+			return
+		}
+		for _, r := range string(b) {
+			if !unicode.IsPrint(r) {
+				return
+			}
+		}
+		d.value = append(d.value, []rune(string(b))...)
+	}
+	d.draw(o.GetPane())
+}
+
+// open creates modal overlay with frame and shadow, sized to fit the dialog,
+// and shows it
+func (d *tuiDialog) open(t *TUI) *TUIOverlay {
+	w := len([]rune(d.title)) + 4
+	for _, l := range d.lines {
+		if len([]rune(l))+4 > w {
+			w = len([]rune(l)) + 4
+		}
+	}
+	bw := 2
+	for _, b := range d.buttons {
+		bw += len([]rune(b)) + 5
+	}
+	if bw > w {
+		w = bw
+	}
+	if d.prompt && w < 40 {
+		w = 40
+	}
+	h := len(d.lines) + 4
+	if d.prompt {
+		h += 2
+	}
+
+	o := NewTUIOverlay("dialog", t, w, h)
+	o.SetModal(true)
+	o.SetShadow(true)
+	o.SetZ(1000)
+	o.GetPane().SetStyle(NewTUIPaneStyleFrame())
+	o.GetPane().SetOnDraw(d.draw)
+	o.SetOnKeyPress(d.keyPress)
+	o.Open()
+	return o
+}
+
+// Alert opens modal dialog with a message and OK button. Function passed as
+// an argument (can be nil) is called when the dialog is closed.
+func (t *TUI) Alert(title string, msg string, f func()) *TUIOverlay {
+	d := &tuiDialog{
+		title:   title,
+		lines:   strings.Split(msg, "\n"),
+		buttons: []string{"OK"},
+		onClose: func(int, string) {
+			if f != nil {
+				f()
+			}
+		},
+	}
+	return d.open(t)
+}
+
+// Confirm opens modal dialog with a question and Yes and No buttons. Function
+// passed as an argument is called with the answer when the dialog is closed.
+// Pressing escape is the same as choosing No.
+func (t *TUI) Confirm(title string, msg string, f func(bool)) *TUIOverlay {
+	d := &tuiDialog{
+		title:   title,
+		lines:   strings.Split(msg, "\n"),
+		buttons: []string{"Yes", "No"},
+		onClose: func(b int, v string) {
+			if f != nil {
+				f(b == 0)
+			}
+		},
+	}
+	return d.open(t)
+}
+
+// Prompt opens modal dialog with a message and a text input with initial
+// value. Function passed as an argument is called with the typed value and
+// false when the dialog was cancelled.
+func (t *TUI) Prompt(title string, msg string, value string, f func(string, bool)) *TUIOverlay {
+	d := &tuiDialog{
+		title:   title,
+		lines:   strings.Split(msg, "\n"),
+		buttons: []string{"OK", "Cancel"},
+		prompt:  true,
+		value:   []rune(value),
+		onClose: func(b int, v string) {
+			if f != nil {
+				f(v, b == 0)
+			}
+		},
+	}
+	return d.open(t)
+}
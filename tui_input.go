@@ -0,0 +1,62 @@
+package terminalui
+
+import (
+	"io"
+	"os"
+	"sync"
+)
+
+// tuiReader reads keyboard input in its own goroutine and passes it through
+// a channel. Read cannot be interrupted, so instead of leaving a goroutine
+// blocked after every Stop, the same reader is used by the next Run (which
+// gets input read in the meantime).
+type tuiReader struct {
+	r   io.Reader
+	ch  chan []byte
+	err error
+}
+
+// stdinReader is shared by all the TUIs reading from os.Stdin
+var stdinReader *tuiReader
+var stdinReaderMu sync.Mutex
+
+// read reads input until there is an error, which is then kept and the
+// channel is closed
+func (rd *tuiReader) read() {
+	for {
+		b := make([]byte, 64)
+		n, err := rd.r.Read(b)
+		if n > 0 {
+			rd.ch <- b[:n]
+		}
+		if err != nil {
+			rd.err = err
+			close(rd.ch)
+			return
+		}
+	}
+}
+
+// getReader returns reader of the input, which is created when it is used
+// for the first time
+func (t *TUI) getReader() *tuiReader {
+	if t.in == os.Stdin {
+		stdinReaderMu.Lock()
+		defer stdinReaderMu.Unlock()
+		if stdinReader == nil {
+			stdinReader = newTUIReader(os.Stdin)
+		}
+		return stdinReader
+	}
+	if t.reader == nil {
+		t.reader = newTUIReader(t.in)
+	}
+	return t.reader
+}
+
+// newTUIReader creates tuiReader and starts reading
+func newTUIReader(r io.Reader) *tuiReader {
+	rd := &tuiReader{r: r, ch: make(chan []byte)}
+	go rd.read()
+	return rd
+}
@@ -0,0 +1,46 @@
+package terminalui
+
+import (
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestRunReusesReader(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	tui := NewTUI()
+	tui.SetInput(r)
+	tui.SetOutput(io.Discard)
+	tui.SetSize(20, 5)
+	keys := make(chan string, 10)
+	tui.SetOnKeyPress(func(tui *TUI, b []byte) {
+		keys <- string(b)
+		if string(b) == "q" {
+			tui.Stop(0)
+		}
+	})
+
+	n := runtime.NumGoroutine()
+	for i := 0; i < 3; i++ {
+		go w.Write([]byte("aq"))
+		if c := tui.Run(nil, nil); c != 0 {
+			t.Fatalf("Run returned %d", c)
+		}
+		for _, k := range []string{"a", "q"} {
+			select {
+			case got := <-keys:
+				if got != k {
+					t.Errorf("got key %q, want %q", got, k)
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("key %q was not passed", k)
+			}
+		}
+	}
+	time.Sleep(time.Millisecond * 50)
+	if d := runtime.NumGoroutine() - n; d > 1 {
+		t.Errorf("%d goroutines left after Run", d)
+	}
+}
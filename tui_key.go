@@ -0,0 +1,85 @@
+package terminalui
+
+import "unicode/utf8"
+
+// Byte sequences sent onto stdio by the terminal when special keys are
+// pressed. They can be compared with the bytes passed to onKeyPress.
+const KEY_UP = "\u001b[A"
+const KEY_DOWN = "\u001b[B"
+const KEY_RIGHT = "\u001b[C"
+const KEY_LEFT = "\u001b[D"
+const KEY_HOME = "\u001b[H"
+const KEY_END = "\u001b[F"
+const KEY_PGUP = "\u001b[5~"
+const KEY_PGDN = "\u001b[6~"
+const KEY_ENTER = "\n"
+const KEY_TAB = "\t"
+const KEY_SPACE = " "
+const KEY_BACKSPACE = "\u007f"
+const KEY_ESC = "\u001b"
+const KEY_ALT_LEFT = "\u001b[1;3D"
+const KEY_ALT_RIGHT = "\u001b[1;3C"
+const KEY_CTRL_UP = "\u001b[1;5A"
+const KEY_CTRL_DOWN = "\u001b[1;5B"
+const KEY_CTRL_RIGHT = "\u001b[1;5C"
+const KEY_CTRL_LEFT = "\u001b[1;5D"
+
+// splitKeys splits bytes read from the keyboard into separate keys, so keys
+// typed quickly or pasted are passed one by one. Escape sequences (eg.
+// arrows or mouse events) are kept together. When more can be read (the
+// buffer was full), escape sequence or character that is not complete is
+// returned as the rest, to be joined with the next read.
+func splitKeys(b []byte, more bool) ([][]byte, []byte) {
+	var keys [][]byte
+	for i := 0; i < len(b); {
+		n, ok := getKeyLength(b[i:])
+		if !ok && more {
+			return keys, b[i:]
+		}
+		keys = append(keys, b[i:i+n])
+		i += n
+	}
+	return keys, nil
+}
+
+// getKeyLength returns number of bytes of the key at the beginning of b, and
+// false when it is cut (in which case the length is of all the bytes)
+func getKeyLength(b []byte) (int, bool) {
+	if b[0] != 0x1b {
+		if b[0] < utf8.RuneSelf {
+			return 1, true
+		}
+		if !utf8.FullRune(b) {
+			return len(b), false
+		}
+		_, n := utf8.DecodeRune(b)
+		return n, true
+	}
+	if len(b) == 1 {
+		return 1, true
+	}
+	switch b[1] {
+	case '[':
+		// CSI: parameters, intermediate bytes and the final byte
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				return i + 1, true
+			}
+			if b[i] < 0x20 || b[i] > 0x3f {
+				return i, true
+			}
+		}
+		return len(b), false
+	case 'O':
+		// SS3, eg. F1 or cursor keys in application mode
+		if len(b) < 3 {
+			return len(b), false
+		}
+		return 3, true
+	case 0x1b:
+		return 1, true
+	}
+	// Alt with a key
+	n, ok := getKeyLength(b[1:])
+	return n + 1, ok
+}
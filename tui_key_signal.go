@@ -0,0 +1,24 @@
+//go:build !windows
+
+package terminalui
+
+import "syscall"
+
+// signalKeys are keys that make terminal send signals
+var signalKeys = map[string]syscall.Signal{
+	"\u0003": syscall.SIGINT,
+	"\u001a": syscall.SIGTSTP,
+	"\u001c": syscall.SIGQUIT,
+}
+
+// raiseSignal sends signal to the process group, like terminal does when
+// key is pressed and generating signals is on. It returns false when key
+// does not send any signal.
+func raiseSignal(k string) bool {
+	sig, ok := signalKeys[k]
+	if !ok {
+		return false
+	}
+	syscall.Kill(0, sig)
+	return true
+}
@@ -0,0 +1,7 @@
+package terminalui
+
+// raiseSignal does nothing on Windows where terminal widget is not
+// supported
+func raiseSignal(k string) bool {
+	return false
+}
@@ -0,0 +1,45 @@
+package terminalui
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		more bool
+		keys []string
+		rest string
+	}{
+		{"characters", "ab", false, []string{"a", "b"}, ""},
+		{"utf-8", "zł€", false, []string{"z", "ł", "€"}, ""},
+		{"arrows", KEY_UP + KEY_DOWN + "x", false, []string{KEY_UP, KEY_DOWN, "x"}, ""},
+		{"modifiers", KEY_CTRL_LEFT + KEY_PGUP, false, []string{KEY_CTRL_LEFT, KEY_PGUP}, ""},
+		{"mouse", "\u001b[<0;3;4M\u001b[<0;3;4m", false, []string{"\u001b[<0;3;4M", "\u001b[<0;3;4m"}, ""},
+		{"ss3", "\u001bOA\u001bOP", false, []string{"\u001bOA", "\u001bOP"}, ""},
+		{"alt", "\u001bx\u001bł", false, []string{"\u001bx", "\u001bł"}, ""},
+		{"escape", "\u001b" + KEY_UP, false, []string{KEY_ESC, KEY_UP}, ""},
+		{"escape at the end", "a\u001b", false, []string{"a", KEY_ESC}, ""},
+		{"cut sequence", "a\u001b[1;", true, []string{"a"}, "\u001b[1;"},
+		{"cut sequence at the end", "a\u001b[1;", false, []string{"a", "\u001b[1;"}, ""},
+		{"cut character", "a\xc5", true, []string{"a"}, "\xc5"},
+		{"invalid byte", "\xffa", false, []string{"\xff", "a"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, rest := splitKeys([]byte(tt.in), tt.more)
+			got := []string{}
+			for _, k := range keys {
+				got = append(got, string(k))
+			}
+			if !reflect.DeepEqual(got, tt.keys) {
+				t.Errorf("keys = %q, want %q", got, tt.keys)
+			}
+			if string(rest) != tt.rest {
+				t.Errorf("rest = %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
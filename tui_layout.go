@@ -0,0 +1,237 @@
+package terminalui
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TUILayout describes a pane and panes inside it so the whole interface can
+// be defined in a JSON or YAML document instead of code. Split is either
+// "vertical", "horizontal", "tabs" or empty. Size and Unit ("%" or "ch")
+// work the same as arguments of SplitVertically and SplitHorizontally.
+// Style is a name of predefined style ("frame", "margin", "none") and
+// Border can be used to define a custom one instead. Widget is a key of the
+// widget registered with TUI.RegisterWidget. Active is the index of the
+// active tab. Collapse is the same as TUIPane.SetCollapse.
+type TUILayout struct {
+	Name      string        `json:"name,omitempty" yaml:"name,omitempty"`
+	Split     string        `json:"split,omitempty" yaml:"split,omitempty"`
+	Size      int           `json:"size,omitempty" yaml:"size,omitempty"`
+	Unit      string        `json:"unit,omitempty" yaml:"unit,omitempty"`
+	MinWidth  int           `json:"min_width,omitempty" yaml:"min_width,omitempty"`
+	MinHeight int           `json:"min_height,omitempty" yaml:"min_height,omitempty"`
+	Style     string        `json:"style,omitempty" yaml:"style,omitempty"`
+	Border    *TUIPaneStyle `json:"border,omitempty" yaml:"border,omitempty"`
+	Widget    string        `json:"widget,omitempty" yaml:"widget,omitempty"`
+	Active    int           `json:"active,omitempty" yaml:"active,omitempty"`
+	Collapse  bool          `json:"collapse,omitempty" yaml:"collapse,omitempty"`
+	Panes     []*TUILayout  `json:"panes,omitempty" yaml:"panes,omitempty"`
+}
+
+var layoutSplits = map[string]int{"": SPLIT_NONE, "vertical": SPLIT_V, "horizontal": SPLIT_H, "tabs": SPLIT_TABS}
+var layoutUnits = map[string]int{"": UNIT_PERCENT, "%": UNIT_PERCENT, "ch": UNIT_CHAR}
+var layoutStyles = map[string]func() *TUIPaneStyle{
+	"frame":  NewTUIPaneStyleFrame,
+	"margin": NewTUIPaneStyleMargin,
+	"none":   NewTUIPaneStyleNone,
+}
+
+// RegisterWidget adds a func that attaches widget to a pane (eg. sets its
+// onDraw and onIterate) under a key that can be used in TUILayout
+func (t *TUI) RegisterWidget(k string, f func(p *TUIPane)) {
+	t.widgets[k] = f
+}
+
+// LoadLayout reads layout in JSON format, builds panes from it and sets them
+// as the main pane
+func (t *TUI) LoadLayout(r io.Reader) error {
+	l := &TUILayout{}
+	err := json.NewDecoder(r).Decode(l)
+	if err != nil {
+		return err
+	}
+	return t.SetLayout(l)
+}
+
+// LoadLayoutYAML reads layout in YAML format, builds panes from it and sets
+// them as the main pane
+func (t *TUI) LoadLayoutYAML(r io.Reader) error {
+	l := &TUILayout{}
+	err := yaml.NewDecoder(r).Decode(l)
+	if err != nil {
+		return err
+	}
+	return t.SetLayout(l)
+}
+
+// SaveLayout writes layout of the main pane in JSON format
+func (t *TUI) SaveLayout(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(t.pane.GetLayout())
+}
+
+// SaveLayoutYAML writes layout of the main pane in YAML format
+func (t *TUI) SaveLayoutYAML(w io.Writer) error {
+	e := yaml.NewEncoder(w)
+	e.SetIndent(2)
+	err := e.Encode(t.pane.GetLayout())
+	if err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// SetLayout builds panes from the layout and sets them as the main pane
+func (t *TUI) SetLayout(l *TUILayout) error {
+	p, err := t.NewTUIPaneFromLayout(l)
+	if err != nil {
+		return err
+	}
+	if !t.isRunning() {
+		t.SetPane(p)
+		return nil
+	}
+	return t.ReplacePane(t.pane, p)
+}
+
+// NewTUIPaneFromLayout returns new instance of TUIPane built from the layout.
+// Pane is not attached anywhere. Panes without names get them generated the
+// same way as in Split.
+func (t *TUI) NewTUIPaneFromLayout(l *TUILayout) (*TUIPane, error) {
+	return t.newTUIPaneFromLayout(l, "main")
+}
+
+// newTUIPaneFromLayout is called recursively by NewTUIPaneFromLayout with
+// the name for the pane when it is missing in the layout
+func (t *TUI) newTUIPaneFromLayout(l *TUILayout, n string) (*TUIPane, error) {
+	if l.Name != "" {
+		n = l.Name
+	}
+	split, ok := layoutSplits[l.Split]
+	if !ok {
+		return nil, errors.New("invalid split '" + l.Split + "' in pane '" + n + "'")
+	}
+	unit, ok := layoutUnits[l.Unit]
+	if !ok {
+		return nil, errors.New("invalid unit '" + l.Unit + "' in pane '" + n + "'")
+	}
+	if (split == SPLIT_V || split == SPLIT_H) && len(l.Panes) != 2 {
+		return nil, errors.New("split pane '" + n + "' must have 2 panes")
+	}
+	if split == SPLIT_NONE && len(l.Panes) > 0 {
+		return nil, errors.New("pane '" + n + "' has panes but no split")
+	}
+	if split == SPLIT_V || split == SPLIT_H {
+		if l.Size == 0 {
+			return nil, errors.New("split pane '" + n + "' must have size")
+		}
+		if unit == UNIT_PERCENT && (l.Size >= 100 || l.Size <= -100) {
+			return nil, errors.New("size of split pane '" + n + "' must be less than 100%")
+		}
+	}
+
+	p := NewTUIPane(n, t)
+	err := t.applyLayout(p, l)
+	if err != nil {
+		return nil, err
+	}
+
+	p.split = split
+	p.splitValue = l.Size
+	p.splitUnit = unit
+	for i, cl := range l.Panes {
+		c, err := t.newTUIPaneFromLayout(cl, n+"."+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		if split == SPLIT_TABS {
+			p.tabs = append(p.tabs, c)
+		} else {
+			p.panes[i] = c
+		}
+		c.parent = p
+	}
+	if l.Active > 0 && l.Active < len(p.tabs) {
+		p.activeTab = l.Active
+	}
+	return p, nil
+}
+
+// applyLayout sets style, widget, minimal sizes and collapsing of the pane
+// from the layout. Fields missing in the layout are cleared (eg. pane gets
+// no style), so nothing is left from the layout used before. Minimal sizes
+// are set before the widget is attached, which may raise them. Widget is
+// not attached again when pane already has it.
+func (t *TUI) applyLayout(p *TUIPane, l *TUILayout) error {
+	var st *TUIPaneStyle
+	if l.Style != "" {
+		f, ok := layoutStyles[l.Style]
+		if !ok {
+			return errors.New("invalid style '" + l.Style + "' in pane '" + p.name + "'")
+		}
+		st = f()
+	} else if l.Border != nil {
+		s := *l.Border
+		st = &s
+	}
+	p.SetStyle(st)
+	p.SetMinWidth(l.MinWidth)
+	p.SetMinHeight(l.MinHeight)
+	p.collapse = l.Collapse
+	if l.Widget != "" && l.Widget != p.widget {
+		f, ok := t.widgets[l.Widget]
+		if !ok {
+			return errors.New("widget '" + l.Widget + "' in pane '" + p.name + "' is not registered")
+		}
+		p.widget = l.Widget
+		f(p)
+	}
+	return nil
+}
+
+// GetLayout returns layout describing the pane and all the panes inside it
+func (p *TUIPane) GetLayout() *TUILayout {
+	l := &TUILayout{
+		Name:      p.name,
+		MinWidth:  p.minWidth,
+		MinHeight: p.minHeight,
+		Widget:    p.widget,
+		Collapse:  p.collapse,
+	}
+	for k, v := range layoutSplits {
+		if v == p.split {
+			l.Split = k
+		}
+	}
+	if p.split == SPLIT_V || p.split == SPLIT_H {
+		l.Size = p.splitValue
+		l.Unit = "%"
+		if p.splitUnit == UNIT_CHAR {
+			l.Unit = "ch"
+		}
+	}
+	if p.style != nil {
+		l.Border = p.style
+		for k, f := range layoutStyles {
+			if *f() == *p.style {
+				l.Style = k
+				l.Border = nil
+			}
+		}
+	}
+	for _, c := range p.panes {
+		if c != nil {
+			l.Panes = append(l.Panes, c.GetLayout())
+		}
+	}
+	for _, c := range p.tabs {
+		l.Panes = append(l.Panes, c.GetLayout())
+	}
+	l.Active = p.activeTab
+	return l
+}
@@ -0,0 +1,203 @@
+package terminalui
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// TUILayoutState is what gets saved to a file between runs when application
+// name is set: layout of the main pane (with split values and active tabs),
+// and names of focused and zoomed panes.
+type TUILayoutState struct {
+	Layout *TUILayout `json:"layout"`
+	Focus  string     `json:"focus,omitempty"`
+	Zoom   string     `json:"zoom,omitempty"`
+}
+
+// GetAppName returns application name
+func (t *TUI) GetAppName() string {
+	return t.appName
+}
+
+// SetAppName sets application name. When it is set, layout adjusted by the
+// user (resized, zoomed or rearranged panes, active tabs) is saved to a file
+// in user's config directory when dragging a border ends and when the
+// interface stops, and it is restored on the next Run. Saved layout is used
+// only when it has the same panes (by name) as the current one.
+func (t *TUI) SetAppName(n string) {
+	t.appName = n
+}
+
+// GetLayoutState returns current layout state
+func (t *TUI) GetLayoutState() *TUILayoutState {
+	s := &TUILayoutState{Layout: t.pane.GetLayout()}
+	if t.focus != nil {
+		s.Focus = t.focus.name
+	}
+	if t.zoomed != nil {
+		s.Zoom = t.zoomed.name
+	}
+	return s
+}
+
+// SetLayoutState rearranges panes according to the layout state. Panes are
+// matched by names and keep their widgets. It returns false when the state
+// does not match current panes.
+func (t *TUI) SetLayoutState(s *TUILayoutState) bool {
+	if s.Layout == nil || !t.matchesLayout(s.Layout) {
+		return false
+	}
+	t.queue(func() {
+		if !t.matchesLayout(s.Layout) {
+			return
+		}
+		t.pane = t.arrangePanes(s.Layout, t.getPanesByName())
+		t.pane.parent = nil
+		t.focus = t.GetPaneByName(s.Focus)
+		t.zoomed = t.GetPaneByName(s.Zoom)
+		if t.zoomed == t.pane {
+			t.zoomed = nil
+		}
+	})
+	return true
+}
+
+// getLayoutStatePath returns path to the file with saved layout state
+func (t *TUI) getLayoutStatePath() (string, error) {
+	d, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(d, t.appName, "layout.json"), nil
+}
+
+// saveLayoutState writes layout state to a file when it changed. Nothing is
+// saved when layout of a breakpoint is used.
+func (t *TUI) saveLayoutState() {
+	if t.appName == "" || t.breakpoint != nil {
+		return
+	}
+	b, err := json.MarshalIndent(t.GetLayoutState(), "", "  ")
+	if err != nil || bytes.Equal(b, t.savedLayout) {
+		return
+	}
+	f, err := t.getLayoutStatePath()
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(f), 0700) != nil {
+		return
+	}
+	if os.WriteFile(f, b, 0600) == nil {
+		t.savedLayout = b
+	}
+}
+
+// restoreLayoutState reads layout state from a file and applies it when it
+// matches current panes
+func (t *TUI) restoreLayoutState() {
+	if t.appName == "" {
+		return
+	}
+	f, err := t.getLayoutStatePath()
+	if err != nil {
+		return
+	}
+	b, err := os.ReadFile(f)
+	if err != nil {
+		return
+	}
+	s := &TUILayoutState{}
+	if json.Unmarshal(b, s) != nil {
+		return
+	}
+	if t.SetLayoutState(s) {
+		t.savedLayout = b
+	}
+}
+
+// getPanesByName returns all the panes of the main pane by their names
+func (t *TUI) getPanesByName() map[string]*TUIPane {
+	m := map[string]*TUIPane{}
+	t.pane.Walk(func(p *TUIPane, d int) bool {
+		m[p.name] = p
+		return true
+	})
+	return m
+}
+
+// matchesLayout checks if layout is valid and has the same panes, which are
+// not split, as the main pane
+func (t *TUI) matchesLayout(l *TUILayout) bool {
+	cur := map[string]bool{}
+	t.pane.Walk(func(p *TUIPane, d int) bool {
+		if p.split == SPLIT_NONE {
+			cur[p.name] = true
+		}
+		return true
+	})
+	names := map[string]bool{}
+	leaves := 0
+	var check func(l *TUILayout) bool
+	check = func(l *TUILayout) bool {
+		split, ok := layoutSplits[l.Split]
+		if !ok || names[l.Name] || l.Name == "" {
+			return false
+		}
+		if _, ok := layoutUnits[l.Unit]; !ok {
+			return false
+		}
+		names[l.Name] = true
+		if split == SPLIT_NONE {
+			leaves++
+			return len(l.Panes) == 0 && cur[l.Name]
+		}
+		if cur[l.Name] || ((split == SPLIT_V || split == SPLIT_H) && len(l.Panes) != 2) {
+			return false
+		}
+		for _, c := range l.Panes {
+			if !check(c) {
+				return false
+			}
+		}
+		return true
+	}
+	return check(l) && leaves == len(cur)
+}
+
+// arrangePanes builds panes described by layout reusing existing ones with
+// the same names, which keep their widgets. All the panes get style, widget,
+// minimal sizes, split, split value and active tab from the layout, and the
+// ones missing in it are cleared. Style or widget that is not known is
+// skipped.
+func (t *TUI) arrangePanes(l *TUILayout, m map[string]*TUIPane) *TUIPane {
+	p := m[l.Name]
+	if p == nil {
+		p = NewTUIPane(l.Name, t)
+	}
+	t.applyLayout(p, l)
+	p.split = layoutSplits[l.Split]
+	p.panes = [2]*TUIPane{}
+	p.tabs = nil
+	p.activeTab = 0
+	if p.split == SPLIT_NONE {
+		return p
+	}
+	p.splitValue = l.Size
+	p.splitUnit = layoutUnits[l.Unit]
+	for i, cl := range l.Panes {
+		c := t.arrangePanes(cl, m)
+		c.parent = p
+		if p.split == SPLIT_TABS {
+			p.tabs = append(p.tabs, c)
+		} else {
+			p.panes[i] = c
+		}
+	}
+	if l.Active > 0 && l.Active < len(p.tabs) {
+		p.activeTab = l.Active
+	}
+	return p
+}
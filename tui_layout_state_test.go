@@ -0,0 +1,94 @@
+package terminalui
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// newStateTUI returns TUI with two framed panes that saves its layout state
+// in a temporary config directory
+func newStateTUI(t *testing.T) (*TUI, *TUIPane, *TUIPane) {
+	tui := newTreeTUI(40, 10)
+	tui.SetAppName("app")
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT, "left", "right")
+	a.SetStyle(NewTUIPaneStyleFrame())
+	b.SetStyle(NewTUIPaneStyleFrame())
+	tui.layout()
+	return tui, a, b
+}
+
+func TestSaveLayoutStateAfterDrag(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	tui, a, b := newStateTUI(t)
+	tui.SetFocus(b)
+
+	// right border of the left pane is dragged 5 characters to the right
+	for _, e := range []*TUIMouseEvent{
+		{Button: MOUSE_LEFT, X: 19, Y: 3, Pressed: true},
+		{Button: MOUSE_LEFT, X: 22, Y: 3, Pressed: true, Motion: true},
+		{Button: MOUSE_LEFT, X: 24, Y: 3, Pressed: true, Motion: true},
+	} {
+		tui.mouseEvent(e)
+		tui.layout()
+	}
+	if a.GetWidth() != 25 {
+		t.Fatalf("left pane width is %d after drag", a.GetWidth())
+	}
+	f, err := tui.getLayoutStatePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(f); !os.IsNotExist(err) {
+		t.Fatal("layout state was saved before drag ended")
+	}
+	tui.mouseEvent(&TUIMouseEvent{Button: MOUSE_LEFT, X: 24, Y: 3})
+
+	bs, err := os.ReadFile(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &TUILayoutState{}
+	if err := json.Unmarshal(bs, s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Layout.Size != tui.GetPane().GetSplitValue() || s.Focus != "right" || s.Zoom != "" {
+		t.Errorf("saved state %s", bs)
+	}
+
+	// next run with the same panes gets the dragged split value and focus
+	next, _, nb := newStateTUI(t)
+	next.restoreLayoutState()
+	if next.GetPane().GetSplitValue() != tui.GetPane().GetSplitValue() || next.GetFocus() != nb {
+		t.Errorf("restored split value %d, focus %v", next.GetPane().GetSplitValue(), next.GetFocus())
+	}
+	next.layout()
+	if next.GetPane().GetPanes()[0].GetWidth() != 25 {
+		t.Errorf("restored left pane width is %d", next.GetPane().GetPanes()[0].GetWidth())
+	}
+}
+
+func TestRestoreLayoutState(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	tui, a, b := newStateTUI(t)
+	tui.SwapPanes(a, b)
+	tui.Zoom(a)
+	tui.saveLayoutState()
+
+	next, na, _ := newStateTUI(t)
+	next.restoreLayoutState()
+	if next.GetPane().GetPanes()[0].GetName() != "right" || next.GetZoomed() != na {
+		t.Errorf("layout was not restored: %v, zoomed %v", next.GetPane().GetPanes(), next.GetZoomed())
+	}
+
+	// layout with other panes is not restored
+	other := newTreeTUI(40, 10)
+	other.SetAppName("app")
+	other.GetPane().SplitVertically(50, UNIT_PERCENT, "left", "other")
+	other.restoreLayoutState()
+	if other.GetPane().GetPanes()[0].GetName() != "left" || other.GetZoomed() != nil {
+		t.Error("layout with different panes was restored")
+	}
+}
@@ -0,0 +1,134 @@
+package terminalui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestLayoutYAML(t *testing.T) {
+	y := `name: main
+split: vertical
+size: -30
+panes:
+  - name: left
+    style: frame
+    min_width: 10
+  - name: right
+    split: tabs
+    active: 1
+    panes:
+      - name: one
+      - name: two
+`
+	tui := NewTUI()
+	if err := tui.LoadLayoutYAML(strings.NewReader(y)); err != nil {
+		t.Fatal(err)
+	}
+	p := tui.GetPane()
+	if p.split != SPLIT_V || p.splitValue != -30 || p.splitUnit != UNIT_PERCENT {
+		t.Errorf("split = %d %d %d", p.split, p.splitValue, p.splitUnit)
+	}
+	if p.panes[0].minWidth != 10 || p.panes[1].activeTab != 1 || len(p.panes[1].tabs) != 2 {
+		t.Errorf("panes were not built from layout")
+	}
+
+	var b bytes.Buffer
+	if err := tui.SaveLayoutYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	tui2 := NewTUI()
+	if err := tui2.LoadLayoutYAML(&b); err != nil {
+		t.Fatal(err)
+	}
+	var j1, j2 bytes.Buffer
+	tui.SaveLayout(&j1)
+	tui2.SaveLayout(&j2)
+	if j1.String() != j2.String() {
+		t.Errorf("layout changed after saving and loading:\n%s\n%s", j1.String(), j2.String())
+	}
+}
+
+func TestLayoutInvalidSize(t *testing.T) {
+	for _, l := range []string{
+		`{"split": "vertical", "panes": [{}, {}]}`,
+		`{"split": "horizontal", "size": 100, "panes": [{}, {}]}`,
+		`{"split": "horizontal", "size": -150, "unit": "%", "panes": [{}, {}]}`,
+	} {
+		if err := NewTUI().LoadLayout(strings.NewReader(l)); err == nil {
+			t.Errorf("no error for %s", l)
+		}
+	}
+	if err := NewTUI().LoadLayout(strings.NewReader(`{"split": "horizontal", "size": 120, "unit": "ch", "panes": [{}, {}]}`)); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBreakpointLayout(t *testing.T) {
+	tui := NewTUI()
+	n := 0
+	tui.RegisterWidget("counter", func(p *TUIPane) {
+		n++
+	})
+	if err := tui.LoadLayout(strings.NewReader(`{"split": "vertical", "size": 50, "panes": [{"name": "a", "widget": "counter"}, {"name": "b"}]}`)); err != nil {
+		t.Fatal(err)
+	}
+	tui.AddBreakpoint(40, 0, &TUILayout{Name: "main", Split: "horizontal", Size: 50, Panes: []*TUILayout{
+		{Name: "a", Widget: "counter", Style: "frame", MinHeight: 3},
+		{Name: "b", Widget: "counter"},
+	}})
+	tui.w, tui.h = 30, 20
+	tui.applyBreakpoint()
+	a, b := tui.GetPaneByName("a"), tui.GetPaneByName("b")
+	if tui.pane.split != SPLIT_H || a.minHeight != 3 || *a.style != *NewTUIPaneStyleFrame() {
+		t.Errorf("breakpoint layout was not applied")
+	}
+	if n != 2 || b.widget != "counter" {
+		t.Errorf("widget was attached %d times, want 2", n)
+	}
+
+	tui.ClosePane(b)
+	if _, ok := tui.breakpointPanes["b"]; ok {
+		t.Errorf("closed pane is kept for breakpoints")
+	}
+}
+
+func TestBreakpointAndBack(t *testing.T) {
+	tui := NewTUI()
+	tui.SetOutput(io.Discard)
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT, "a", "b")
+	b.SetStyle(NewTUIPaneStyleMargin())
+	b.SetMinWidth(3)
+	tui.AddBreakpoint(40, 0, &TUILayout{
+		Name:  "main",
+		Split: "horizontal",
+		Size:  50,
+		Unit:  "%",
+		Panes: []*TUILayout{
+			{Name: "a", Style: "frame", MinWidth: 10, MinHeight: 2},
+			{Name: "b"},
+		},
+	})
+
+	tui.SetSize(30, 10)
+	tui.refreshSize()
+	if tui.GetPane().split != SPLIT_H || a.style == nil || a.minWidth != 10 || a.minHeight != 2 {
+		t.Fatalf("breakpoint layout was not applied")
+	}
+	if b.style != nil || b.minWidth != 0 {
+		t.Errorf("pane kept style and minimal width of the default layout")
+	}
+
+	tui.SetSize(100, 10)
+	tui.refreshSize()
+	if tui.GetPane().split != SPLIT_V {
+		t.Fatalf("default layout was not applied")
+	}
+	if a.style != nil || a.minWidth != 0 || a.minHeight != 0 {
+		t.Errorf("pane kept style or minimal sizes of the breakpoint: %v %d %d", a.style, a.minWidth, a.minHeight)
+	}
+	if b.style == nil || *b.style != *NewTUIPaneStyleMargin() || b.minWidth != 3 {
+		t.Errorf("pane did not get back its style and minimal width")
+	}
+}
@@ -0,0 +1,60 @@
+package terminalui
+
+import (
+	"strconv"
+	"strings"
+)
+
+const MOUSE_LEFT = 0
+const MOUSE_MIDDLE = 1
+const MOUSE_RIGHT = 2
+const MOUSE_WHEEL_UP = 64
+const MOUSE_WHEEL_DOWN = 65
+
+// TUIMouseEvent represents mouse button press or release reported by the
+// terminal. X and Y are the same as the ones used in Write.
+type TUIMouseEvent struct {
+	Button  int
+	X       int
+	Y       int
+	Pressed bool
+	Motion  bool
+}
+
+// parseMouseEvents takes bytes sent onto stdio and returns mouse events
+// encoded in them (SGR mode). It returns false when bytes are not a mouse
+// report.
+func parseMouseEvents(b []byte) ([]*TUIMouseEvent, bool) {
+	s := string(b)
+	if !strings.HasPrefix(s, "\u001b[<") {
+		return nil, false
+	}
+	es := []*TUIMouseEvent{}
+	for _, r := range strings.Split(s, "\u001b[<")[1:] {
+		if len(r) < 6 {
+			continue
+		}
+		end := strings.IndexAny(r, "Mm")
+		if end == -1 {
+			continue
+		}
+		nums := strings.Split(r[:end], ";")
+		if len(nums) != 3 {
+			continue
+		}
+		c, err1 := strconv.Atoi(nums[0])
+		x, err2 := strconv.Atoi(nums[1])
+		y, err3 := strconv.Atoi(nums[2])
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		es = append(es, &TUIMouseEvent{
+			Button:  c &^ (4 | 8 | 16 | 32),
+			X:       x - 1,
+			Y:       y - 1,
+			Pressed: r[end] == 'M',
+			Motion:  c&32 != 0,
+		})
+	}
+	return es, true
+}
@@ -0,0 +1,119 @@
+package terminalui
+
+import (
+	"reflect"
+	"testing"
+)
+
+// paneName returns name of the pane or empty string when it is nil
+func paneName(p *TUIPane) string {
+	if p == nil {
+		return ""
+	}
+	return p.GetName()
+}
+
+func TestParseMouseEvents(t *testing.T) {
+	for _, tt := range []struct {
+		s    string
+		want []*TUIMouseEvent
+		ok   bool
+	}{
+		{s: "\u001b[<0;1;1M", want: []*TUIMouseEvent{{Button: MOUSE_LEFT, X: 0, Y: 0, Pressed: true}}, ok: true},
+		{s: "\u001b[<2;10;5m", want: []*TUIMouseEvent{{Button: MOUSE_RIGHT, X: 9, Y: 4}}, ok: true},
+		{s: "\u001b[<32;3;4M", want: []*TUIMouseEvent{{Button: MOUSE_LEFT, X: 2, Y: 3, Pressed: true, Motion: true}}, ok: true},
+		{s: "\u001b[<20;3;4M", want: []*TUIMouseEvent{{Button: MOUSE_LEFT, X: 2, Y: 3, Pressed: true}}, ok: true},
+		{s: "\u001b[<64;1;2M\u001b[<65;1;2M", want: []*TUIMouseEvent{
+			{Button: MOUSE_WHEEL_UP, X: 0, Y: 1, Pressed: true},
+			{Button: MOUSE_WHEEL_DOWN, X: 0, Y: 1, Pressed: true},
+		}, ok: true},
+		{s: "\u001b[<0;1M", want: []*TUIMouseEvent{}, ok: true},
+		{s: "\u001b[<x;1;1M", want: []*TUIMouseEvent{}, ok: true},
+		{s: "\u001b[A"},
+		{s: "a"},
+	} {
+		got, ok := parseMouseEvents([]byte(tt.s))
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: got %v %v", tt.s, got, ok)
+		}
+	}
+}
+
+func TestGetPaneAt(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a, b := tui.GetPane().SplitVertically(-10, UNIT_CHAR, "a", "b")
+	c, d := b.SplitHorizontally(50, UNIT_PERCENT, "c", "d")
+	x := d.AddTab("x")
+	d.AddTab("y")
+	o := NewTUIOverlay("o", tui, 4, 2)
+	o.SetPosition(36, 0)
+	tui.layout()
+	for _, tt := range []struct {
+		x    int
+		y    int
+		want *TUIPane
+	}{
+		{x: 0, y: 0, want: a},
+		{x: 9, y: 9, want: a},
+		{x: 10, y: 0, want: c},
+		{x: 39, y: 4, want: c},
+		{x: 10, y: 5, want: d},
+		{x: 10, y: 6, want: x},
+		{x: 39, y: 9, want: x},
+		{x: 37, y: 1, want: c},
+		{x: 40, y: 0},
+		{x: 0, y: 10},
+		{x: -1, y: 0},
+	} {
+		if got := tui.GetPaneAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("%d,%d: got %q, want %q", tt.x, tt.y, paneName(got), paneName(tt.want))
+		}
+	}
+
+	o.Open()
+	tui.Zoom(c)
+	tui.layout()
+	for _, tt := range []struct {
+		x    int
+		y    int
+		want *TUIPane
+	}{
+		{x: 37, y: 1, want: o.GetPane()},
+		{x: 0, y: 9, want: c},
+		{x: 35, y: 0, want: c},
+	} {
+		if got := tui.GetPaneAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("zoomed %d,%d: got %q, want %q", tt.x, tt.y, paneName(got), paneName(tt.want))
+		}
+	}
+}
+
+func TestStartDrag(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	c, d := b.SplitHorizontally(50, UNIT_PERCENT)
+	for _, p := range []*TUIPane{a, c, d} {
+		p.SetStyle(NewTUIPaneStyleFrame())
+	}
+	tui.layout()
+	for _, tt := range []struct {
+		name string
+		x    int
+		y    int
+		want *TUIPane
+	}{
+		{name: "left pane right border", x: 19, y: 2, want: tui.GetPane()},
+		{name: "top pane left border", x: 20, y: 2, want: tui.GetPane()},
+		{name: "top pane bottom border", x: 25, y: 4, want: b},
+		{name: "bottom pane top border", x: 25, y: 5, want: b},
+		{name: "outer border", x: 0, y: 2},
+		{name: "top border", x: 5, y: 0},
+		{name: "inside", x: 5, y: 5},
+	} {
+		tui.drag = nil
+		ok := tui.startDrag(&TUIMouseEvent{Button: MOUSE_LEFT, X: tt.x, Y: tt.y, Pressed: true})
+		if ok != (tt.want != nil) || tui.drag != tt.want {
+			t.Errorf("%s: got %v, dragging %q", tt.name, ok, paneName(tui.drag))
+		}
+	}
+}
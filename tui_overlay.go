@@ -0,0 +1,192 @@
+package terminalui
+
+// TUIOverlay is a floating pane that is drawn on top of the main pane (and
+// other overlays with lower z-order). It can be centered or positioned at
+// specific place on the terminal window. When it is modal, it captures all
+// the keyboard and mouse input. Panes fully covered by an overlay are not
+// iterated, and all the panes under it are redrawn when it is closed.
+type TUIOverlay struct {
+	pane       *TUIPane
+	tui        *TUI
+	x          int
+	y          int
+	width      int
+	height     int
+	centered   bool
+	z          int
+	shadow     bool
+	modal      bool
+	onKeyPress func(*TUIOverlay, []byte)
+}
+
+// GetPane returns the pane that is shown in the overlay
+func (o *TUIOverlay) GetPane() *TUIPane {
+	return o.pane
+}
+
+// GetTUI returns TUI instance that this overlay is attached to
+func (o *TUIOverlay) GetTUI() *TUI {
+	return o.tui
+}
+
+// GetZ returns z-order
+func (o *TUIOverlay) GetZ() int {
+	return o.z
+}
+
+// IsModal returns true when overlay captures all the input
+func (o *TUIOverlay) IsModal() bool {
+	return o.modal
+}
+
+// IsOpen returns true when overlay is shown
+func (o *TUIOverlay) IsOpen() bool {
+	for _, x := range o.tui.overlays {
+		if x == o {
+			return true
+		}
+	}
+	return false
+}
+
+// SetPosition sets position of the overlay on terminal window. Overlay is
+// not centered anymore.
+func (o *TUIOverlay) SetPosition(x int, y int) {
+	o.x = x
+	o.y = y
+	o.centered = false
+	o.refresh()
+}
+
+// SetCentered sets overlay to be centered on terminal window
+func (o *TUIOverlay) SetCentered(b bool) {
+	o.centered = b
+	o.refresh()
+}
+
+// SetSize sets overlay width and height
+func (o *TUIOverlay) SetSize(w int, h int) {
+	o.width = w
+	o.height = h
+	o.refresh()
+}
+
+// SetZ sets z-order. Overlays with higher value are drawn on top.
+func (o *TUIOverlay) SetZ(z int) {
+	o.tui.queue(func() {
+		o.z = z
+		o.tui.sortOverlays()
+	})
+}
+
+// SetShadow sets whether drop shadow should be drawn
+func (o *TUIOverlay) SetShadow(b bool) {
+	o.shadow = b
+	o.refresh()
+}
+
+// SetModal sets whether overlay captures all the input
+func (o *TUIOverlay) SetModal(b bool) {
+	o.modal = b
+}
+
+// SetOnKeyPress attaches function that will be triggered when key is pressed
+// and the overlay is the top modal one
+func (o *TUIOverlay) SetOnKeyPress(f func(*TUIOverlay, []byte)) {
+	o.onKeyPress = f
+}
+
+// Open shows the overlay
+func (o *TUIOverlay) Open() {
+	o.tui.OpenOverlay(o)
+}
+
+// Close hides the overlay
+func (o *TUIOverlay) Close() {
+	o.tui.CloseOverlay(o)
+}
+
+// refresh redraws the interface if overlay is shown
+func (o *TUIOverlay) refresh() {
+	if o.IsOpen() {
+		o.tui.Refresh()
+	}
+}
+
+// layout calculates overlay position and sets its pane size
+func (o *TUIOverlay) layout() {
+	w, h := o.width, o.height
+	if w > o.tui.w {
+		w = o.tui.w
+	}
+	if h > o.tui.h {
+		h = o.tui.h
+	}
+	x, y := o.x, o.y
+	if o.centered {
+		x = (o.tui.w - w) / 2
+		y = (o.tui.h - h) / 2
+	}
+	if x+w > o.tui.w {
+		x = o.tui.w - w
+	}
+	if y+h > o.tui.h {
+		y = o.tui.h - h
+	}
+	if x < 0 {
+		x = 0
+	}
+	if y < 0 {
+		y = 0
+	}
+	o.pane.SetLeft(x)
+	o.pane.SetTop(y)
+	o.pane.SetWidth(w)
+	o.pane.SetHeight(h)
+}
+
+// getArea returns position and size of the overlay including its shadow
+func (o *TUIOverlay) getArea() (int, int, int, int) {
+	x, y, w, h := o.pane.left, o.pane.top, o.pane.width, o.pane.height
+	if o.shadow {
+		w++
+		h++
+	}
+	return x, y, w, h
+}
+
+// draw clears area under the overlay and draws its pane and shadow
+func (o *TUIOverlay) draw() {
+	if o.pane.width <= 0 || o.pane.height <= 0 {
+		return
+	}
+	o.pane.clear()
+	o.pane.Draw()
+	if !o.shadow {
+		return
+	}
+	x, y, w, h := o.pane.left, o.pane.top, o.pane.width, o.pane.height
+	if x+w < o.tui.w {
+		for i := y + 1; i <= y+h && i < o.tui.h; i++ {
+			o.tui.Write(x+w, i, "░")
+		}
+	}
+	if y+h < o.tui.h {
+		for i := x + 1; i < x+w && i < o.tui.w; i++ {
+			o.tui.Write(i, y+h, "░")
+		}
+	}
+}
+
+// NewTUIOverlay returns new instance of TUIOverlay with a pane of specific
+// name and size. Overlay is centered by default.
+func NewTUIOverlay(n string, t *TUI, w int, h int) *TUIOverlay {
+	o := &TUIOverlay{
+		pane:     NewTUIPane(n, t),
+		tui:      t,
+		width:    w,
+		height:   h,
+		centered: true,
+	}
+	return o
+}
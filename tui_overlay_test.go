@@ -0,0 +1,200 @@
+package terminalui
+
+import (
+	"testing"
+)
+
+// overlayNames returns names of panes of the open overlays
+func overlayNames(t *TUI) string {
+	s := ""
+	for _, o := range t.GetOverlays() {
+		s += o.GetPane().GetName()
+	}
+	return s
+}
+
+func TestOverlayOrder(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a := NewTUIOverlay("a", tui, 10, 5)
+	b := NewTUIOverlay("b", tui, 10, 5)
+	c := NewTUIOverlay("c", tui, 10, 5)
+	a.SetZ(2)
+	b.SetZ(1)
+	c.SetZ(2)
+	for _, tt := range []struct {
+		name string
+		f    func()
+		want string
+	}{
+		{name: "open", f: func() { a.Open(); b.Open(); c.Open() }, want: "bac"},
+		{name: "open again", f: a.Open, want: "bac"},
+		{name: "z up", f: func() { b.SetZ(3) }, want: "acb"},
+		{name: "close", f: c.Close, want: "ab"},
+		{name: "close again", f: c.Close, want: "ab"},
+	} {
+		tt.f()
+		if got := overlayNames(tui); got != tt.want {
+			t.Errorf("%s: got overlays %q, want %q", tt.name, got, tt.want)
+		}
+	}
+	if c.IsOpen() || !a.IsOpen() {
+		t.Error("IsOpen is wrong")
+	}
+}
+
+func TestOverlayLayout(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	o := NewTUIOverlay("o", tui, 10, 4)
+	o.Open()
+	for _, tt := range []struct {
+		name string
+		f    func()
+		want [4]int
+	}{
+		{name: "centered", f: func() {}, want: [4]int{15, 3, 10, 4}},
+		{name: "position", f: func() { o.SetPosition(2, 1) }, want: [4]int{2, 1, 10, 4}},
+		{name: "off screen", f: func() { o.SetPosition(35, 8) }, want: [4]int{30, 6, 10, 4}},
+		{name: "too big", f: func() { o.SetSize(50, 20) }, want: [4]int{0, 0, 40, 10}},
+		{name: "centered again", f: func() { o.SetSize(6, 2); o.SetCentered(true) }, want: [4]int{17, 4, 6, 2}},
+	} {
+		tt.f()
+		tui.layout()
+		if got := paneArea(o.GetPane()); got != tt.want {
+			t.Errorf("%s: overlay area is %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOverlayCoversPanes(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	o := NewTUIOverlay("o", tui, 25, 10)
+	o.SetPosition(0, 0)
+	o.Open()
+	tui.layout()
+	if !a.covered || b.covered || !b.overlapped {
+		t.Errorf("covered %v %v, overlapped %v", a.covered, b.covered, b.overlapped)
+	}
+	if tui.GetPaneAt(22, 1) != o.GetPane() || tui.GetPaneAt(25, 1) != b {
+		t.Error("overlay is not on top")
+	}
+	o.Close()
+	tui.layout()
+	if a.covered {
+		t.Error("pane is still covered after overlay was closed")
+	}
+}
+
+func TestModalOverlay(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	tui.SetFocus(a)
+	bound := 0
+	tui.BindKey("x", func(t *TUI) {
+		bound++
+	})
+	var keys []string
+	o := NewTUIOverlay("o", tui, 10, 4)
+	o.SetModal(true)
+	o.SetOnKeyPress(func(o *TUIOverlay, k []byte) {
+		keys = append(keys, string(k))
+	})
+	o.Open()
+	tui.layout()
+
+	tui.input([]byte("x"))
+	tui.mouseEvent(&TUIMouseEvent{Button: MOUSE_LEFT, X: 25, Y: 0, Pressed: true})
+	if bound != 0 || len(keys) != 1 || keys[0] != "x" {
+		t.Errorf("key went to bindings %d times, overlay got %q", bound, keys)
+	}
+	if tui.GetFocus() != a {
+		t.Error("click went through modal overlay")
+	}
+
+	o.Close()
+	tui.input([]byte("x"))
+	tui.mouseEvent(&TUIMouseEvent{Button: MOUSE_LEFT, X: 25, Y: 0, Pressed: true})
+	if bound != 1 || tui.GetFocus() != b {
+		t.Error("input did not go to interface after overlay was closed")
+	}
+}
+
+func TestDialogs(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		open func(tui *TUI, got *string) *TUIOverlay
+		keys []string
+		want string
+	}{
+		{
+			name: "alert",
+			open: func(tui *TUI, got *string) *TUIOverlay {
+				return tui.Alert("Title", "Message", func() { *got = "closed" })
+			},
+			keys: []string{KEY_RIGHT, KEY_ENTER},
+			want: "closed",
+		},
+		{
+			name: "confirm yes",
+			open: func(tui *TUI, got *string) *TUIOverlay {
+				return tui.Confirm("Title", "Sure?", func(b bool) { *got = map[bool]string{true: "yes", false: "no"}[b] })
+			},
+			keys: []string{KEY_ENTER},
+			want: "yes",
+		},
+		{
+			name: "confirm no",
+			open: func(tui *TUI, got *string) *TUIOverlay {
+				return tui.Confirm("Title", "Sure?", func(b bool) { *got = map[bool]string{true: "yes", false: "no"}[b] })
+			},
+			keys: []string{KEY_TAB, KEY_ENTER},
+			want: "no",
+		},
+		{
+			name: "confirm escape",
+			open: func(tui *TUI, got *string) *TUIOverlay {
+				return tui.Confirm("Title", "Sure?", func(b bool) { *got = map[bool]string{true: "yes", false: "no"}[b] })
+			},
+			keys: []string{KEY_ESC},
+			want: "no",
+		},
+		{
+			name: "prompt",
+			open: func(tui *TUI, got *string) *TUIOverlay {
+				return tui.Prompt("Title", "Name:", "ab", func(v string, ok bool) { *got = v + map[bool]string{true: "+", false: "-"}[ok] })
+			},
+			keys: []string{"c", KEY_BACKSPACE, KEY_BACKSPACE, "ż", KEY_UP, KEY_ENTER},
+			want: "aż+",
+		},
+		{
+			name: "prompt cancel",
+			open: func(tui *TUI, got *string) *TUIOverlay {
+				return tui.Prompt("Title", "Name:", "ab", func(v string, ok bool) { *got = v + map[bool]string{true: "+", false: "-"}[ok] })
+			},
+			keys: []string{"c", KEY_RIGHT, KEY_ENTER},
+			want: "abc-",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tui := newTreeTUI(60, 20)
+			got := ""
+			o := tt.open(tui, &got)
+			tui.layout()
+			if !o.IsOpen() || !o.IsModal() {
+				t.Fatal("dialog is not open or not modal")
+			}
+			for _, k := range tt.keys {
+				if got != "" {
+					t.Fatalf("dialog closed before %q", k)
+				}
+				tui.input([]byte(k))
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if o.IsOpen() {
+				t.Error("dialog is still open")
+			}
+		})
+	}
+}
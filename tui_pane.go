@@ -2,11 +2,15 @@ package terminalui
 
 import (
 	"math"
+	"strconv"
+	"strings"
+	"time"
 )
 
 const SPLIT_NONE = 0
 const SPLIT_H = 1
 const SPLIT_V = 2
+const SPLIT_TABS = 3
 
 const UNIT_PERCENT = 1
 const UNIT_CHAR = 2
@@ -16,25 +20,39 @@ const UNIT_CHAR = 2
 // Split can be described as percentage or fixed characters and only one
 // of the panes created from split can have fixed size. Other one is calculated
 // from total width.
+// Pane can also be a tabs container which holds many panes but shows only
+// one of them at a time.
 // Pane also have min width, min height, style and have two events: onDraw
-// and onIterate.
+// and onIterate. Each pane can have its own iterate interval and timers.
 type TUIPane struct {
-	name       string
-	split      int
-	splitValue int
-	splitUnit  int
-	tooSmall   bool
-	tui        *TUI
-	panes      [2]*TUIPane
-	onDraw     func(p *TUIPane) int
-	onIterate  func(p *TUIPane) int
-	width      int
-	height     int
-	left       int
-	top        int
-	minWidth   int
-	minHeight  int
-	style      *TUIPaneStyle
+	name            string
+	split           int
+	splitValue      int
+	splitUnit       int
+	tooSmall        bool
+	collapse        bool
+	covered         bool
+	overlapped      bool
+	tui             *TUI
+	parent          *TUIPane
+	panes           [2]*TUIPane
+	tabs            []*TUIPane
+	activeTab       int
+	onDraw          func(p *TUIPane) int
+	onIterate       func(p *TUIPane) int
+	iterateInterval int
+	lastIterate     time.Time
+	width           int
+	height          int
+	left            int
+	top             int
+	minWidth        int
+	minHeight       int
+	style           *TUIPaneStyle
+	widget          string
+	panic           *TUIPaneError
+	log             *TUIWidgetLog
+	terminal        *TUIWidgetTerminal
 }
 
 // GetName returns name
@@ -42,6 +60,11 @@ func (p *TUIPane) GetName() string {
 	return p.name
 }
 
+// SetName sets name
+func (p *TUIPane) SetName(n string) {
+	p.name = n
+}
+
 // GetSplit returns split type (horizontal or vertical)
 func (p *TUIPane) GetSplit() int {
 	return p.split
@@ -52,6 +75,22 @@ func (p *TUIPane) GetTUI() *TUI {
 	return p.tui
 }
 
+// GetParent returns pane that was split to create this pane or nil
+func (p *TUIPane) GetParent() *TUIPane {
+	return p.parent
+}
+
+// GetSplitValue returns size of one of the panes created by split (see
+// SplitVertically and SplitHorizontally)
+func (p *TUIPane) GetSplitValue() int {
+	return p.splitValue
+}
+
+// GetSplitUnit returns unit of split value
+func (p *TUIPane) GetSplitUnit() int {
+	return p.splitUnit
+}
+
 // GetPanes returns pane instances created by split
 func (p *TUIPane) GetPanes() [2]*TUIPane {
 	return p.panes
@@ -72,6 +111,12 @@ func (p *TUIPane) GetStyle() *TUIPaneStyle {
 	return p.style
 }
 
+// GetWidget returns key of the widget attached to the pane when it was built
+// from TUILayout
+func (p *TUIPane) GetWidget() string {
+	return p.widget
+}
+
 // SetOnDraw sets onDraw event func
 func (p *TUIPane) SetOnDraw(f func(p *TUIPane) int) {
 	p.onDraw = f
@@ -89,31 +134,72 @@ func (p *TUIPane) SetStyle(s *TUIPaneStyle) {
 
 // Split creates new two panes by splitting this pane either
 // horizontally or vertically.
-// Type, size, size unit are func arguments.
+// Type, size, size unit are func arguments. Optionally, names of the new
+// panes can be passed as well. Otherwise they are generated from this pane
+// name, eg. "main.0" and "main.1". Names must be unique, so when any pane
+// already has the name, a number is added to it (eg. "log-2").
 // Function returns pointers to two new panes.
-func (p *TUIPane) Split(t int, s int, u int) (*TUIPane, *TUIPane) {
-	p.panes[0] = NewTUIPane("Nazwa", p.tui)
-	p.panes[1] = NewTUIPane("Nazwa", p.tui)
-	p.split = t
-	p.splitValue = s
-	p.splitUnit = u
-	return p.panes[0], p.panes[1]
+// If pane is already split then its current panes are moved into the first
+// new pane.
+// When interface is running, the split is done on the UI goroutine, and the
+// new panes get their final names then.
+func (p *TUIPane) Split(t int, s int, u int, n ...string) (*TUIPane, *TUIPane) {
+	c0, c1, f := p.prepareSplit(t, s, u, n...)
+	p.tui.queue(f)
+	return c0, c1
+}
+
+// prepareSplit creates new panes for Split and returns them with function
+// that splits the pane, which must be called on the UI goroutine
+func (p *TUIPane) prepareSplit(t int, s int, u int, n ...string) (*TUIPane, *TUIPane, func()) {
+	n0, n1 := p.name+".0", p.name+".1"
+	if len(n) > 0 && n[0] != "" {
+		n0 = n[0]
+	}
+	if len(n) > 1 && n[1] != "" {
+		n1 = n[1]
+	}
+	c0 := NewTUIPane(n0, p.tui)
+	c1 := NewTUIPane(n1, p.tui)
+	return c0, c1, func() {
+		c0.name = p.tui.uniqueName(n0)
+		c1.name = p.tui.uniqueName(n1, c0.name)
+		if p.split != SPLIT_NONE {
+			c0.split, c0.splitValue, c0.splitUnit = p.split, p.splitValue, p.splitUnit
+			c0.panes, c0.tabs, c0.activeTab = p.panes, p.tabs, p.activeTab
+			for _, c := range c0.panes {
+				if c != nil {
+					c.parent = c0
+				}
+			}
+			for _, c := range c0.tabs {
+				c.parent = c0
+			}
+			p.tabs = nil
+		}
+		c0.parent = p
+		c1.parent = p
+		p.panes = [2]*TUIPane{c0, c1}
+		p.split = t
+		p.splitValue = s
+		p.splitUnit = u
+	}
 }
 
 // SplitVertically splits pane vertically. It takes size and size unit
 // as arguments. Only one of the two new panes gets the defined size. If the
 // value is < 0 then it's the left one, when the value is > 0 then it is
-// the right one.
-func (p *TUIPane) SplitVertically(s int, u int) (*TUIPane, *TUIPane) {
-	return p.Split(SPLIT_V, s, u)
+// the right one. Names of the new panes are optional.
+func (p *TUIPane) SplitVertically(s int, u int, n ...string) (*TUIPane, *TUIPane) {
+	return p.Split(SPLIT_V, s, u, n...)
 }
 
 // SplitHorizontally splits pane horizontally. It takes size and size unit
 // as arguments. Only one of the two new panes gets the defined size. If the
 // value is < 0 then it's the top one, when the value is > 0 then it is the
-// right one.
-func (p *TUIPane) SplitHorizontally(s int, u int) (*TUIPane, *TUIPane) {
-	return p.Split(SPLIT_H, s, u)
+// right one. Names of the new panes are optional.
+func (p *TUIPane) SplitHorizontally(s int, u int, n ...string) (*TUIPane, *TUIPane) {
+	return p.Split(SPLIT_H, s, u, n...)
 }
 
 // GetWidth returns pane width
@@ -164,6 +250,63 @@ func (p *TUIPane) GetTotalMinHeight() int {
 	return p.minHeight
 }
 
+// GetContentWidth returns width available for pane content, which is pane
+// width without the style borders
+func (p *TUIPane) GetContentWidth() int {
+	if p.style != nil {
+		return p.width - p.style.H()
+	}
+	return p.width
+}
+
+// GetContentHeight returns height available for pane content, which is pane
+// height without the style borders
+func (p *TUIPane) GetContentHeight() int {
+	if p.style != nil {
+		return p.height - p.style.V()
+	}
+	return p.height
+}
+
+// getRequiredWidth returns width necessary for the pane and all the panes
+// inside it to work
+func (p *TUIPane) getRequiredWidth() int {
+	r := 0
+	if p.split == SPLIT_V && !p.collapse {
+		r = p.panes[0].getRequiredWidth() + p.panes[1].getRequiredWidth()
+	} else if p.split == SPLIT_H || p.split == SPLIT_V {
+		r = int(math.Max(float64(p.panes[0].getRequiredWidth()), float64(p.panes[1].getRequiredWidth())))
+	} else if p.split == SPLIT_TABS {
+		for _, c := range p.tabs {
+			r = int(math.Max(float64(r), float64(c.getRequiredWidth())))
+		}
+		if p.style != nil {
+			r += p.style.H()
+		}
+	}
+	return int(math.Max(math.Max(float64(r), float64(p.GetTotalMinWidth())), 1))
+}
+
+// getRequiredHeight returns height necessary for the pane and all the panes
+// inside it to work
+func (p *TUIPane) getRequiredHeight() int {
+	r := 0
+	if p.split == SPLIT_H && !p.collapse {
+		r = p.panes[0].getRequiredHeight() + p.panes[1].getRequiredHeight()
+	} else if p.split == SPLIT_V || p.split == SPLIT_H {
+		r = int(math.Max(float64(p.panes[0].getRequiredHeight()), float64(p.panes[1].getRequiredHeight())))
+	} else if p.split == SPLIT_TABS {
+		for _, c := range p.tabs {
+			r = int(math.Max(float64(r), float64(c.getRequiredHeight())))
+		}
+		r++
+		if p.style != nil {
+			r += p.style.V()
+		}
+	}
+	return int(math.Max(math.Max(float64(r), float64(p.GetTotalMinHeight())), 1))
+}
+
 // SetWidth sets width of pane, checks if it's not too small for the content
 // (search for 'minimal width') and calls panes inside to set their width as
 // well.
@@ -181,6 +324,9 @@ func (p *TUIPane) SetWidth(w int) {
 		p.panes[1].SetWidth(w)
 	} else if p.split == SPLIT_V {
 		v1, v2, tooSmall := p.getSplitValues()
+		if p.collapse {
+			v1, v2, tooSmall = p.getCollapsedSplitValues(v1, v2, tooSmall)
+		}
 		if tooSmall {
 			p.tooSmall = true
 			return
@@ -190,6 +336,16 @@ func (p *TUIPane) SetWidth(w int) {
 		p.panes[1].SetLeft(p.left + v1)
 		p.panes[0].SetWidth(v1)
 		p.panes[1].SetWidth(v2)
+	} else if p.split == SPLIT_TABS {
+		l, cw := p.left, w
+		if p.style != nil {
+			l += p.style.L()
+			cw -= p.style.H()
+		}
+		for _, c := range p.tabs {
+			c.SetLeft(l)
+			c.SetWidth(cw)
+		}
 	}
 }
 
@@ -209,6 +365,9 @@ func (p *TUIPane) SetHeight(h int) {
 		p.panes[1].SetHeight(h)
 	} else if p.split == SPLIT_H {
 		v1, v2, tooSmall := p.getSplitValues()
+		if p.collapse {
+			v1, v2, tooSmall = p.getCollapsedSplitValues(v1, v2, tooSmall)
+		}
 		if tooSmall {
 			p.tooSmall = true
 			return
@@ -218,6 +377,21 @@ func (p *TUIPane) SetHeight(h int) {
 		p.panes[1].SetTop(p.top + v1)
 		p.panes[0].SetHeight(v1)
 		p.panes[1].SetHeight(v2)
+	} else if p.split == SPLIT_TABS {
+		// first line is taken by the tab bar
+		t, ch := p.top+1, h-1
+		if p.style != nil {
+			t += p.style.T()
+			ch -= p.style.V()
+		}
+		if ch < 1 {
+			p.tooSmall = true
+			return
+		}
+		for _, c := range p.tabs {
+			c.SetTop(t)
+			c.SetHeight(ch)
+		}
 	}
 }
 
@@ -264,6 +438,48 @@ func (p *TUIPane) getSplitValues() (int, int, bool) {
 	return 0, 0, false
 }
 
+// getCollapsedSplitValues takes values calculated by getSplitValues and,
+// when any of the panes is too small, gives the whole space to the other one.
+// It returns true when both panes are too small.
+func (p *TUIPane) getCollapsedSplitValues(v1 int, v2 int, tooSmall bool) (int, int, bool) {
+	base := p.width
+	if p.split == SPLIT_H {
+		base = p.height
+	}
+	r0 := p.panes[0].getRequiredSize(p.split)
+	r1 := p.panes[1].getRequiredSize(p.split)
+	fail0 := tooSmall || v1 < r0
+	fail1 := tooSmall || v2 < r1
+	if !fail0 && !fail1 {
+		return v1, v2, false
+	}
+	if fail0 && !fail1 {
+		return 0, base, false
+	}
+	if fail1 && !fail0 {
+		return base, 0, false
+	}
+	if base >= r0 {
+		return base, 0, false
+	}
+	if base >= r1 {
+		return 0, base, false
+	}
+	return 0, 0, true
+}
+
+// GetCollapse returns true when too small pane created by split gives its
+// space to the other one
+func (p *TUIPane) GetCollapse() bool {
+	return p.collapse
+}
+
+// SetCollapse sets whether pane created by split, which is too small, should
+// be hidden and the other pane should take the whole space
+func (p *TUIPane) SetCollapse(b bool) {
+	p.collapse = b
+}
+
 // SetLeft sets the left value (x position on main pane)
 func (p *TUIPane) SetLeft(l int) {
 	p.left = l
@@ -276,7 +492,7 @@ func (p *TUIPane) SetTop(t int) {
 
 // Write prints string on the pane
 func (p *TUIPane) Write(x int, y int, s string, overwriteStyleFrame bool) {
-	if p.split == SPLIT_NONE || p.tooSmall {
+	if p.split == SPLIT_NONE || p.split == SPLIT_TABS || p.tooSmall {
 		if p.style != nil && !overwriteStyleFrame {
 			p.tui.Write(p.left+x+p.style.L(), p.top+y+p.style.T(), s)
 		} else {
@@ -285,49 +501,186 @@ func (p *TUIPane) Write(x int, y int, s string, overwriteStyleFrame bool) {
 	}
 }
 
-// Draw prints the pane on terminal window
+// Draw prints the pane on terminal window. It returns value returned by
+// onDraw or the more important one of the values returned by panes inside
+// (see RESULT_OK).
 func (p *TUIPane) Draw() int {
+	if p.width <= 0 || p.height <= 0 {
+		return RESULT_OK
+	}
 	if p.tooSmall {
-		if p.width > 0 && p.height > 0 {
-			p.Write(0, 0, "!", false)
-		}
-		return 1
+		p.drawTooSmall()
+		return RESULT_OK
 	}
-	if p.split != SPLIT_NONE {
-		p.panes[0].Draw()
-		p.panes[1].Draw()
-		return 1
+	if p.split == SPLIT_TABS {
+		if p.style != nil {
+			p.style.Draw(p)
+		}
+		p.drawTabBar()
+		if len(p.tabs) > 0 {
+			return p.tabs[p.activeTab].Draw()
+		}
+		return RESULT_OK
+	} else if p.split != SPLIT_NONE {
+		return mergeResults(p.panes[0].Draw(), p.panes[1].Draw())
 	} else {
 		if p.style != nil {
 			p.style.Draw(p)
 		}
+		if p.panic != nil {
+			p.drawPanic()
+			return RESULT_OK
+		}
 		if p.onDraw != nil {
-			return p.onDraw(p)
+			return p.handleResult(p.call(p.onDraw), false)
 		}
-		return 1
+		return RESULT_OK
 	}
-	return 1
 }
 
-// Iterate is executed by TUI with every main loop iteration
+// Iterate is executed by TUI with every main loop iteration. It calls
+// onIterate of the panes which iterate interval has passed. It returns value
+// the same way as Draw.
 func (p *TUIPane) Iterate() int {
+	return p.iterate(time.Now())
+}
+
+// iterate is called by Iterate with time of the current main loop iteration
+func (p *TUIPane) iterate(now time.Time) int {
+	if p.width <= 0 || p.height <= 0 {
+		return RESULT_OK
+	}
 	if p.tooSmall {
-		if p.width > 0 && p.height > 0 {
-			p.Write(0, 0, "!", false)
-		}
-		return 1
+		p.drawTooSmall()
+		return RESULT_OK
 	}
-	if p.split != SPLIT_NONE {
-		p.panes[0].Iterate()
-		p.panes[1].Iterate()
-		return 1
+	if p.split == SPLIT_TABS {
+		if len(p.tabs) > 0 {
+			return p.tabs[p.activeTab].iterate(now)
+		}
+		return RESULT_OK
+	} else if p.split != SPLIT_NONE {
+		return mergeResults(p.panes[0].iterate(now), p.panes[1].iterate(now))
 	} else {
-		if p.onIterate != nil {
-			return p.onIterate(p)
+		if p.onIterate != nil && p.panic == nil && !p.covered && !now.Before(p.lastIterate.Add(p.getIterateInterval())) {
+			p.lastIterate = now
+			if p.overlapped && p.tui != nil {
+				p.tui.overdrawn = true
+			}
+			return p.handleResult(p.call(p.onIterate), true)
+		}
+		return RESULT_OK
+	}
+}
+
+// drawTooSmall is called instead of drawing the pane when it is too small.
+// It calls onTooSmall func attached to TUI, and when there is none, it prints
+// out a message in the middle when it is the top pane and the message is
+// enabled with TUI.SetTooSmallMessage, or '!' otherwise.
+func (p *TUIPane) drawTooSmall() {
+	rw, rh := p.getRequiredWidth(), p.getRequiredHeight()
+	if p.tui != nil && p.tui.onTooSmall != nil {
+		p.tui.onTooSmall(p, rw, rh, p.width, p.height)
+		return
+	}
+	if p.tui == nil || !p.tui.tooSmallMessage || p != p.tui.getVisiblePane() {
+		p.Write(0, 0, "!", false)
+		return
+	}
+	lines := []string{
+		"terminal too small",
+		"need " + strconv.Itoa(rw) + "x" + strconv.Itoa(rh) + ", have " + strconv.Itoa(p.width) + "x" + strconv.Itoa(p.height),
+	}
+	y := (p.height - len(lines)) / 2
+	for i, l := range lines {
+		if y+i < 0 || y+i >= p.height {
+			continue
+		}
+		x := (p.width - len(l)) / 2
+		if x < 0 {
+			x = 0
 		}
-		return 1
+		p.tui.Write(p.left+x, p.top+y+i, fitString(l, p.width-x))
+	}
+}
+
+// mouseEvent passes mouse event to the pane under the cursor. It returns
+// true when the event has been handled (eg. tab got clicked).
+func (p *TUIPane) mouseEvent(e *TUIMouseEvent) bool {
+	if p.tooSmall || !p.contains(e.X, e.Y) {
+		return false
+	}
+	if p.split == SPLIT_TABS {
+		if e.Pressed && e.Button == MOUSE_LEFT && !e.Motion && p.clickTabBar(e.X, e.Y) {
+			return true
+		}
+		if len(p.tabs) > 0 {
+			return p.tabs[p.activeTab].mouseEvent(e)
+		}
+	} else if p.split != SPLIT_NONE {
+		return p.panes[0].mouseEvent(e) || p.panes[1].mouseEvent(e)
+	}
+	return false
+}
+
+// getPaneAt returns pane (one that is not split) which is shown at specific
+// point on terminal window or nil
+func (p *TUIPane) getPaneAt(x int, y int) *TUIPane {
+	if !p.contains(x, y) {
+		return nil
+	}
+	if p.split == SPLIT_TABS {
+		if len(p.tabs) > 0 {
+			if c := p.tabs[p.activeTab].getPaneAt(x, y); c != nil {
+				return c
+			}
+		}
+		return p
+	} else if p.split != SPLIT_NONE {
+		if c := p.panes[0].getPaneAt(x, y); c != nil {
+			return c
+		}
+		return p.panes[1].getPaneAt(x, y)
+	}
+	return p
+}
+
+// contains returns true when point on terminal window is inside the pane
+func (p *TUIPane) contains(x int, y int) bool {
+	return x >= p.left && x < p.left+p.width && y >= p.top && y < p.top+p.height
+}
+
+// setCovered marks panes that are under any of the overlays. Panes that are
+// fully covered are not iterated, and overlays are drawn again after panes
+// that are partly covered print out something.
+func (p *TUIPane) setCovered(os []*TUIOverlay) {
+	p.covered = false
+	p.overlapped = false
+	for _, o := range os {
+		x, y, w, h := o.getArea()
+		if p.left < x+w && x < p.left+p.width && p.top < y+h && y < p.top+p.height {
+			p.overlapped = true
+		}
+		if x <= p.left && p.left+p.width <= x+w && y <= p.top && p.top+p.height <= y+h {
+			p.covered = true
+			break
+		}
+	}
+	for _, c := range p.panes {
+		if c != nil {
+			c.setCovered(os)
+		}
+	}
+	for _, c := range p.tabs {
+		c.setCovered(os)
+	}
+}
+
+// clear prints out spaces over the whole pane area
+func (p *TUIPane) clear() {
+	for i := 0; i < p.height; i++ {
+		p.tui.Write(p.left, p.top+i, strings.Repeat(" ", p.width))
 	}
-	return 1
 }
 
 // NewTUIPane returns new instance of TUIPane
@@ -0,0 +1,114 @@
+package terminalui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Walk calls function for the pane and all the panes inside it (including
+// inactive tabs), passing the depth (0 for this pane). Pane position and size
+// can be taken with GetLeft, GetTop, GetWidth and GetHeight. When function
+// returns false, panes inside the visited one are skipped.
+func (p *TUIPane) Walk(f func(p *TUIPane, depth int) bool) {
+	p.walk(f, 0)
+}
+
+// walk is called recursively by Walk
+func (p *TUIPane) walk(f func(p *TUIPane, depth int) bool, d int) {
+	if !f(p, d) {
+		return
+	}
+	for _, c := range p.panes {
+		if c != nil {
+			c.walk(f, d+1)
+		}
+	}
+	for _, c := range p.tabs {
+		c.walk(f, d+1)
+	}
+}
+
+// GetPath returns path of the pane which is made of the top pane name and
+// positions of the following panes, eg. "main/0/1" is the second pane of
+// the first pane of the main pane.
+func (p *TUIPane) GetPath() string {
+	if p.parent == nil {
+		return p.name
+	}
+	return p.parent.GetPath() + "/" + strconv.Itoa(p.parent.indexOf(p))
+}
+
+// Walk calls function for the main pane, overlay panes and all the panes
+// inside them. See TUIPane.Walk.
+func (t *TUI) Walk(f func(p *TUIPane, depth int) bool) {
+	t.pane.Walk(f)
+	for _, o := range t.overlays {
+		o.pane.Walk(f)
+	}
+}
+
+// GetPaneByName returns the first pane with specific name or nil
+func (t *TUI) GetPaneByName(n string) *TUIPane {
+	var r *TUIPane
+	t.Walk(func(p *TUIPane, d int) bool {
+		if r == nil && p.name == n {
+			r = p
+		}
+		return r == nil
+	})
+	return r
+}
+
+// GetPaneByPath returns pane by its path (see TUIPane.GetPath) or nil
+func (t *TUI) GetPaneByPath(s string) *TUIPane {
+	parts := strings.Split(s, "/")
+	var p *TUIPane
+	if t.pane.name == parts[0] {
+		p = t.pane
+	} else {
+		for _, o := range t.overlays {
+			if o.pane.name == parts[0] {
+				p = o.pane
+				break
+			}
+		}
+	}
+	for _, x := range parts[1:] {
+		if p == nil {
+			return nil
+		}
+		i, err := strconv.Atoi(x)
+		if err != nil || i < 0 {
+			return nil
+		}
+		if p.split == SPLIT_TABS && i < len(p.tabs) {
+			p = p.tabs[i]
+		} else if (p.split == SPLIT_H || p.split == SPLIT_V) && i < 2 {
+			p = p.panes[i]
+		} else {
+			return nil
+		}
+	}
+	return p
+}
+
+// uniqueName returns name with a number added when any pane or one of the
+// taken names already has it. It must be called on the UI goroutine.
+func (t *TUI) uniqueName(n string, taken ...string) string {
+	used := func(x string) bool {
+		for _, y := range taken {
+			if x == y {
+				return true
+			}
+		}
+		return t != nil && t.GetPaneByName(x) != nil
+	}
+	if !used(n) {
+		return n
+	}
+	for i := 2; ; i++ {
+		if !used(n + "-" + strconv.Itoa(i)) {
+			return n + "-" + strconv.Itoa(i)
+		}
+	}
+}
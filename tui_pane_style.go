@@ -6,14 +6,14 @@ import (
 
 // TUIPaneStyle defined pane style
 type TUIPaneStyle struct {
-	NE string
-	N  string
-	NW string
-	W  string
-	SW string
-	S  string
-	SE string
-	E  string
+	NE string `yaml:"NE"`
+	N  string `yaml:"N"`
+	NW string `yaml:"NW"`
+	W  string `yaml:"W"`
+	SW string `yaml:"SW"`
+	S  string `yaml:"S"`
+	SE string `yaml:"SE"`
+	E  string `yaml:"E"`
 }
 
 // H (horizontal) returns minimal width for borders
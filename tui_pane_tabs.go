@@ -0,0 +1,146 @@
+package terminalui
+
+import (
+	"math"
+	"strconv"
+)
+
+// AddTab turns the pane into a tabs container (if it is not one already)
+// and adds a new pane as a tab. Tab name is shown on the tab bar at the top.
+// Only active tab is drawn and iterated, but all of them get their sizes set.
+// If pane is split then its current panes are moved into the first tab,
+// named like the first pane of a split (eg. "main.0").
+// Function returns pointer to the new pane.
+func (p *TUIPane) AddTab(n string) *TUIPane {
+	c := NewTUIPane(n, p.tui)
+	p.tui.queue(func() {
+		if p.split != SPLIT_TABS {
+			p.tabs = []*TUIPane{}
+			if p.split != SPLIT_NONE {
+				f := NewTUIPane(p.tui.uniqueName(p.name+".0"), p.tui)
+				f.split, f.splitValue, f.splitUnit = p.split, p.splitValue, p.splitUnit
+				f.panes = p.panes
+				for _, c := range f.panes {
+					c.parent = f
+				}
+				f.parent = p
+				p.tabs = append(p.tabs, f)
+			}
+			p.split = SPLIT_TABS
+			p.panes = [2]*TUIPane{}
+			p.activeTab = 0
+		}
+		c.parent = p
+		p.tabs = append(p.tabs, c)
+	})
+	return c
+}
+
+// GetTabs returns pane instances added as tabs
+func (p *TUIPane) GetTabs() []*TUIPane {
+	return p.tabs
+}
+
+// GetActiveTab returns index of the tab that is shown
+func (p *TUIPane) GetActiveTab() int {
+	return p.activeTab
+}
+
+// SetActiveTab switches to another tab and redraws the pane
+func (p *TUIPane) SetActiveTab(i int) {
+	if p.split != SPLIT_TABS || i < 0 || i >= len(p.tabs) || i == p.activeTab {
+		return
+	}
+	p.activeTab = i
+	p.Invalidate()
+}
+
+// NextTab switches to the tab on the right (or the first one)
+func (p *TUIPane) NextTab() {
+	if len(p.tabs) > 0 {
+		p.SetActiveTab((p.activeTab + 1) % len(p.tabs))
+	}
+}
+
+// PrevTab switches to the tab on the left (or the last one)
+func (p *TUIPane) PrevTab() {
+	if len(p.tabs) > 0 {
+		p.SetActiveTab((p.activeTab + len(p.tabs) - 1) % len(p.tabs))
+	}
+}
+
+// BindTabKeys attaches keyboard shortcuts to switch tabs of this pane:
+// alt+1 to alt+9 to choose a tab, alt+left and alt+right to move to the
+// previous or next one.
+func (p *TUIPane) BindTabKeys() {
+	for i := 1; i <= 9; i++ {
+		j := i - 1
+		p.tui.BindKey(KEY_ESC+strconv.Itoa(i), func(t *TUI) {
+			p.SetActiveTab(j)
+		})
+	}
+	p.tui.BindKey(KEY_ALT_LEFT, func(t *TUI) {
+		p.PrevTab()
+	})
+	p.tui.BindKey(KEY_ALT_RIGHT, func(t *TUI) {
+		p.NextTab()
+	})
+}
+
+// tabBarPositions returns x where each tab label starts and ends on the tab
+// bar (relative to the pane content)
+func (p *TUIPane) tabBarPositions() [][2]int {
+	pos := [][2]int{}
+	x := 0
+	for _, c := range p.tabs {
+		l := len([]rune(c.name)) + 2
+		pos = append(pos, [2]int{x, x + l})
+		x += l + 1
+	}
+	return pos
+}
+
+// drawTabBar prints tab names on the first line of the pane with the active
+// one highlighted
+func (p *TUIPane) drawTabBar() {
+	cw := p.GetContentWidth()
+	end := 0
+	for i, r := range p.tabBarPositions() {
+		if r[0] >= cw {
+			break
+		}
+		l := fitString(" "+p.tabs[i].name+" ", int(math.Min(float64(r[1]), float64(cw)))-r[0])
+		if i == p.activeTab {
+			l = "\u001b[7m" + l + "\u001b[0m"
+		}
+		p.Write(r[0], 0, l, false)
+		end = r[1]
+		if r[1] < cw && i < len(p.tabs)-1 {
+			p.Write(r[1], 0, "│", false)
+			end++
+		}
+	}
+	if end < cw {
+		p.Write(end, 0, fitString("", cw-end), false)
+	}
+}
+
+// clickTabBar switches tab when x and y point to its label on the tab bar.
+// It returns false when the point is not on the tab bar.
+func (p *TUIPane) clickTabBar(x int, y int) bool {
+	l, t := p.left, p.top
+	if p.style != nil {
+		l += p.style.L()
+		t += p.style.T()
+	}
+	if y != t {
+		return false
+	}
+	for i, r := range p.tabBarPositions() {
+		if x-l >= r[0] && x-l < r[1] {
+			p.SetActiveTab(i)
+			return true
+		}
+	}
+	return true
+}
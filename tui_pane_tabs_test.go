@@ -0,0 +1,103 @@
+package terminalui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddTab(t *testing.T) {
+	tui := NewTUI()
+	p := tui.GetPane()
+	a := p.AddTab("one")
+	b := p.AddTab("two")
+	if p.split != SPLIT_TABS || len(p.GetTabs()) != 2 || p.GetTabs()[0] != a || p.GetTabs()[1] != b {
+		t.Fatalf("got tabs %v", p.GetTabs())
+	}
+	if a.GetParent() != p || b.GetParent() != p || p.GetActiveTab() != 0 {
+		t.Error("tabs were not attached")
+	}
+}
+
+func TestAddTabToSplitPane(t *testing.T) {
+	tui := NewTUI()
+	p := tui.GetPane()
+	l, r := p.SplitVertically(-20, UNIT_CHAR)
+	c := p.AddTab("new")
+	tabs := p.GetTabs()
+	if len(tabs) != 2 || tabs[1] != c {
+		t.Fatalf("got tabs %v", tabs)
+	}
+	f := tabs[0]
+	if f.GetName() != "main.0-2" || f.GetParent() != p {
+		t.Errorf("first tab is %q", f.GetName())
+	}
+	if f.split != SPLIT_V || f.splitValue != -20 || f.splitUnit != UNIT_CHAR {
+		t.Errorf("first tab got split %d %d %d", f.split, f.splitValue, f.splitUnit)
+	}
+	if f.panes[0] != l || f.panes[1] != r || l.GetParent() != f || r.GetParent() != f {
+		t.Error("panes were not moved into the first tab")
+	}
+	if p.panes[0] != nil || p.panes[1] != nil {
+		t.Error("split panes were left in tabs container")
+	}
+	if tui.GetPaneByName("main.0") != l || tui.GetPaneByName("main.1") != r {
+		t.Error("moved panes are not found by name")
+	}
+}
+
+func TestSwitchTabs(t *testing.T) {
+	tui := NewTUI()
+	p := tui.GetPane()
+	for _, n := range []string{"one", "two", "three"} {
+		p.AddTab(n)
+	}
+	for _, tt := range []struct {
+		name string
+		f    func()
+		want int
+	}{
+		{name: "set", f: func() { p.SetActiveTab(2) }, want: 2},
+		{name: "set negative", f: func() { p.SetActiveTab(-1) }, want: 2},
+		{name: "set too big", f: func() { p.SetActiveTab(3) }, want: 2},
+		{name: "next wraps", f: p.NextTab, want: 0},
+		{name: "prev wraps", f: p.PrevTab, want: 2},
+		{name: "prev", f: p.PrevTab, want: 1},
+		{name: "next", f: p.NextTab, want: 2},
+	} {
+		tt.f()
+		if p.GetActiveTab() != tt.want {
+			t.Errorf("%s: active tab is %d, want %d", tt.name, p.GetActiveTab(), tt.want)
+		}
+	}
+}
+
+func TestTabBarClick(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	p := tui.GetPane()
+	for _, n := range []string{"one", "two", "three"} {
+		p.AddTab(n)
+	}
+	tui.layout()
+	tui.draw()
+	if l := strings.TrimRight(tui.GetScreen().GetLine(0), " "); l != " one │ two │ three" {
+		t.Errorf("tab bar is %q", l)
+	}
+	for _, tt := range []struct {
+		name string
+		x    int
+		y    int
+		want int
+	}{
+		{name: "second", x: 7, y: 0, want: 1},
+		{name: "third start", x: 12, y: 0, want: 2},
+		{name: "separator", x: 11, y: 0, want: 2},
+		{name: "first end", x: 4, y: 0, want: 0},
+		{name: "after labels", x: 30, y: 0, want: 0},
+		{name: "content", x: 7, y: 1, want: 0},
+	} {
+		tui.mouseEvent(&TUIMouseEvent{Button: MOUSE_LEFT, X: tt.x, Y: tt.y, Pressed: true})
+		if p.GetActiveTab() != tt.want {
+			t.Errorf("%s: active tab is %d, want %d", tt.name, p.GetActiveTab(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package terminalui
+
+import (
+	"io"
+	"runtime"
+	"testing"
+)
+
+func TestSplitNames(t *testing.T) {
+	tui := NewTUI()
+	p := tui.GetPane()
+	a, b := p.SplitVertically(50, UNIT_PERCENT, "log", "log")
+	if a.GetName() != "log" || b.GetName() != "log-2" {
+		t.Errorf("got names %q and %q", a.GetName(), b.GetName())
+	}
+	c, d := b.SplitHorizontally(50, UNIT_PERCENT, "log")
+	if c.GetName() != "log-3" || d.GetName() != "log-2.1" {
+		t.Errorf("got names %q and %q", c.GetName(), d.GetName())
+	}
+	if tui.GetPaneByName("log") != a || tui.GetPaneByName("log-3") != c {
+		t.Error("panes were not found by their names")
+	}
+}
+
+func TestSplitWhileRunning(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+	tui := NewTUI()
+	tui.SetInput(r)
+	tui.SetOutput(io.Discard)
+	tui.SetSize(40, 10)
+	done := make(chan int)
+	go func() {
+		done <- tui.Run(nil, nil)
+	}()
+	for !tui.isRunning() {
+		runtime.Gosched()
+	}
+
+	// split is called from other goroutine than the UI one
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT, "main", "x")
+	var an, bn string
+	tui.PostAndWait(func() {
+		an, bn = a.GetName(), b.GetName()
+	})
+	if an != "main-2" || bn != "x" {
+		t.Errorf("got names %q and %q", an, bn)
+	}
+	tui.Stop(0)
+	<-done
+}
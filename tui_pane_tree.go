@@ -0,0 +1,198 @@
+package terminalui
+
+import (
+	"errors"
+)
+
+// ClosePane removes pane from the interface. When pane was created by split,
+// the other pane of the split takes its place (and the whole space). When
+// pane is a tab, the tab is removed. When pane is shown in an overlay, the
+// overlay is closed. Main pane cannot be closed.
+// When the interface is running, the change is applied on the UI goroutine
+// which then redraws the interface. Pane is checked before that so, like
+// other functions returning an error, it must be called on the UI goroutine
+// (eg. from a key binding or with Post) when panes can be changed at the
+// same time.
+func (t *TUI) ClosePane(p *TUIPane) error {
+	if p == t.pane {
+		return errors.New("main pane cannot be closed")
+	}
+	if p.parent == nil && t.getOverlayByPane(p) == nil {
+		return errors.New("pane is not attached")
+	}
+	t.queue(func() {
+		if p.parent == nil {
+			if o := t.getOverlayByPane(p); o != nil {
+				t.CloseOverlay(o)
+			}
+			return
+		}
+		s := p.parent
+		if s.split == SPLIT_TABS {
+			for i, c := range s.tabs {
+				if c == p {
+					s.tabs = append(s.tabs[:i], s.tabs[i+1:]...)
+					if i < s.activeTab {
+						s.activeTab--
+					}
+					break
+				}
+			}
+			if s.activeTab >= len(s.tabs) && s.activeTab > 0 {
+				s.activeTab = len(s.tabs) - 1
+			}
+		} else {
+			o := s.panes[0]
+			if o == p {
+				o = s.panes[1]
+			}
+			t.replacePane(s, o)
+			if t.zoomed == s {
+				t.zoomed = o
+			}
+		}
+		p.parent = nil
+		t.forgetPane(p)
+	})
+	return nil
+}
+
+// SwapPanes swaps places of two panes. Panes cannot be inside one another.
+func (t *TUI) SwapPanes(a *TUIPane, b *TUIPane) error {
+	if a.parent == nil || b.parent == nil {
+		return errors.New("main pane cannot be swapped")
+	}
+	if a == b || a.isAncestorOf(b) || b.isAncestorOf(a) {
+		return errors.New("panes cannot be inside one another")
+	}
+	t.queue(func() {
+		if a.parent == nil || b.parent == nil {
+			return
+		}
+		pa, pb := a.parent, b.parent
+		ia, ib := pa.indexOf(a), pb.indexOf(b)
+		pa.setChild(ia, b)
+		pb.setChild(ib, a)
+	})
+	return nil
+}
+
+// RotateSplit changes orientation of the split, from vertical to horizontal
+// and the other way around
+func (t *TUI) RotateSplit(p *TUIPane) error {
+	if p.split != SPLIT_H && p.split != SPLIT_V {
+		return errors.New("pane is not split vertically or horizontally")
+	}
+	t.queue(func() {
+		if p.split == SPLIT_H {
+			p.split = SPLIT_V
+		} else if p.split == SPLIT_V {
+			p.split = SPLIT_H
+		}
+	})
+	return nil
+}
+
+// ReplacePane puts another pane (possibly split into more panes) in place of
+// an existing one. New pane must not be attached anywhere.
+func (t *TUI) ReplacePane(p *TUIPane, n *TUIPane) error {
+	if n.parent != nil || n == t.pane || t.getOverlayByPane(n) != nil {
+		return errors.New("new pane is already attached")
+	}
+	if p.parent == nil && p != t.pane && t.getOverlayByPane(p) == nil {
+		return errors.New("pane is not attached")
+	}
+	t.queue(func() {
+		t.replacePane(p, n)
+		if t.zoomed == p {
+			t.zoomed = n
+		}
+		t.forgetPane(p)
+	})
+	return nil
+}
+
+// replacePane puts n in place of p in its parent, main pane or overlay
+func (t *TUI) replacePane(p *TUIPane, n *TUIPane) {
+	if p.parent != nil {
+		p.parent.setChild(p.parent.indexOf(p), n)
+		p.parent = nil
+		return
+	}
+	n.parent = nil
+	if t.pane == p {
+		t.pane = n
+	}
+	if o := t.getOverlayByPane(p); o != nil {
+		o.pane = n
+	}
+}
+
+// forgetPane removes references to pane that is not attached anymore and
+// stops its timers
+func (t *TUI) forgetPane(p *TUIPane) {
+	t.stopTimers(p)
+	p.closeTerminals()
+	if t.zoomed != nil && (t.zoomed == p || p.isAncestorOf(t.zoomed)) {
+		t.zoomed = nil
+	}
+	if t.focus != nil && (t.focus == p || p.isAncestorOf(t.focus)) {
+		t.focus = nil
+	}
+	if t.drag != nil && (t.drag == p || p.isAncestorOf(t.drag)) {
+		t.drag = nil
+	}
+	for n, c := range t.breakpointPanes {
+		if c == p || p.isAncestorOf(c) {
+			delete(t.breakpointPanes, n)
+		}
+	}
+}
+
+// getOverlayByPane returns overlay which pane is p or nil
+func (t *TUI) getOverlayByPane(p *TUIPane) *TUIOverlay {
+	for _, o := range t.overlays {
+		if o.pane == p {
+			return o
+		}
+	}
+	return nil
+}
+
+// indexOf returns position of child pane in panes or tabs
+func (p *TUIPane) indexOf(c *TUIPane) int {
+	if p.split == SPLIT_TABS {
+		for i, x := range p.tabs {
+			if x == c {
+				return i
+			}
+		}
+		return -1
+	}
+	for i, x := range p.panes {
+		if x == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// setChild puts pane at specific position in panes or tabs
+func (p *TUIPane) setChild(i int, c *TUIPane) {
+	if p.split == SPLIT_TABS {
+		p.tabs[i] = c
+	} else {
+		p.panes[i] = c
+	}
+	c.parent = p
+}
+
+// isAncestorOf returns true when c is inside the pane
+func (p *TUIPane) isAncestorOf(c *TUIPane) bool {
+	for a := c.parent; a != nil; a = a.parent {
+		if a == p {
+			return true
+		}
+	}
+	return false
+}
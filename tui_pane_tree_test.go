@@ -0,0 +1,177 @@
+package terminalui
+
+import (
+	"testing"
+)
+
+func TestClosePane(t *testing.T) {
+	tui := NewTUI()
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	c, d := b.SplitHorizontally(50, UNIT_PERCENT)
+	tui.SetFocus(c)
+	tm := c.Every(1000, func(p *TUIPane) {})
+
+	if err := tui.ClosePane(c); err != nil {
+		t.Fatal(err)
+	}
+	if tui.GetPane().GetPanes()[1] != d || d.GetParent() != tui.GetPane() {
+		t.Error("other pane of the split did not take its place")
+	}
+	if c.GetParent() != nil || tui.GetPaneByName(c.GetName()) != nil {
+		t.Error("closed pane is still attached")
+	}
+	if tui.GetFocus() != nil {
+		t.Error("closed pane still has focus")
+	}
+	if !tm.IsStopped() {
+		t.Error("timer of closed pane was not stopped")
+	}
+
+	tui.Zoom(a)
+	if err := tui.ClosePane(a); err != nil {
+		t.Fatal(err)
+	}
+	if tui.GetPane() != d || d.GetParent() != nil {
+		t.Error("last pane did not become the main pane")
+	}
+	if tui.GetZoomed() != nil {
+		t.Error("closed pane is still zoomed")
+	}
+
+	for _, tt := range []struct {
+		name string
+		p    *TUIPane
+	}{
+		{name: "main", p: d},
+		{name: "closed", p: a},
+		{name: "not attached", p: NewTUIPane("x", tui)},
+	} {
+		if err := tui.ClosePane(tt.p); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestCloseTab(t *testing.T) {
+	tui := NewTUI()
+	p := tui.GetPane()
+	x := p.AddTab("x")
+	y := p.AddTab("y")
+	z := p.AddTab("z")
+	for _, tt := range []struct {
+		name   string
+		active int
+		close  *TUIPane
+		tabs   []*TUIPane
+		want   int
+	}{
+		{name: "before active", active: 2, close: x, tabs: []*TUIPane{y, z}, want: 1},
+		{name: "active last", active: 1, close: z, tabs: []*TUIPane{y}, want: 0},
+		{name: "only", active: 0, close: y, tabs: []*TUIPane{}, want: 0},
+	} {
+		p.SetActiveTab(tt.active)
+		if err := tui.ClosePane(tt.close); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(p.GetTabs()) != len(tt.tabs) {
+			t.Fatalf("%s: got %d tabs", tt.name, len(p.GetTabs()))
+		}
+		for i, c := range tt.tabs {
+			if p.GetTabs()[i] != c {
+				t.Errorf("%s: tab %d is %q", tt.name, i, p.GetTabs()[i].GetName())
+			}
+		}
+		if p.GetActiveTab() != tt.want {
+			t.Errorf("%s: active tab is %d, want %d", tt.name, p.GetActiveTab(), tt.want)
+		}
+	}
+}
+
+func TestCloseOverlayPane(t *testing.T) {
+	tui := NewTUI()
+	o := NewTUIOverlay("o", tui, 10, 5)
+	o.Open()
+	if err := tui.ClosePane(o.GetPane()); err != nil {
+		t.Fatal(err)
+	}
+	if o.IsOpen() {
+		t.Error("overlay is still open")
+	}
+}
+
+func TestSwapPanes(t *testing.T) {
+	tui := NewTUI()
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	c, d := b.SplitHorizontally(50, UNIT_PERCENT)
+	if err := tui.SwapPanes(a, d); err != nil {
+		t.Fatal(err)
+	}
+	if tui.GetPane().GetPanes()[0] != d || b.GetPanes()[1] != a || d.GetParent() != tui.GetPane() || a.GetParent() != b {
+		t.Error("panes were not swapped")
+	}
+	for _, tt := range []struct {
+		name string
+		a    *TUIPane
+		b    *TUIPane
+	}{
+		{name: "main", a: tui.GetPane(), b: c},
+		{name: "same", a: c, b: c},
+		{name: "ancestor", a: b, b: c},
+		{name: "descendant", a: c, b: b},
+	} {
+		if err := tui.SwapPanes(tt.a, tt.b); err == nil {
+			t.Errorf("%s: no error", tt.name)
+		}
+	}
+}
+
+func TestRotateSplit(t *testing.T) {
+	tui := NewTUI()
+	p := tui.GetPane()
+	a, _ := p.SplitVertically(50, UNIT_PERCENT)
+	for _, want := range []int{SPLIT_H, SPLIT_V} {
+		if err := tui.RotateSplit(p); err != nil {
+			t.Fatal(err)
+		}
+		if p.GetSplit() != want {
+			t.Errorf("split is %d, want %d", p.GetSplit(), want)
+		}
+	}
+	if err := tui.RotateSplit(a); err == nil {
+		t.Error("pane that is not split was rotated")
+	}
+}
+
+func TestReplacePane(t *testing.T) {
+	tui := NewTUI()
+	_, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	tui.SetFocus(b)
+	n := NewTUIPane("new", tui)
+	n.SplitHorizontally(50, UNIT_PERCENT)
+	if err := tui.ReplacePane(b, n); err != nil {
+		t.Fatal(err)
+	}
+	if tui.GetPane().GetPanes()[1] != n || n.GetParent() != tui.GetPane() || b.GetParent() != nil {
+		t.Error("pane was not replaced")
+	}
+	if tui.GetFocus() != nil {
+		t.Error("replaced pane still has focus")
+	}
+	if tui.GetPaneByName("new.1") == nil {
+		t.Error("panes of the new pane are not found")
+	}
+	if err := tui.ReplacePane(b, NewTUIPane("x", tui)); err == nil {
+		t.Error("pane that is not attached was replaced")
+	}
+	if err := tui.ReplacePane(tui.GetPane().GetPanes()[0], n); err == nil {
+		t.Error("pane was replaced with an attached one")
+	}
+
+	m := NewTUIPane("other", tui)
+	if err := tui.ReplacePane(tui.GetPane(), m); err != nil {
+		t.Fatal(err)
+	}
+	if tui.GetPane() != m || m.GetParent() != nil {
+		t.Error("main pane was not replaced")
+	}
+}
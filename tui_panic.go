@@ -0,0 +1,155 @@
+package terminalui
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// TUIPanic is an error made of a value passed to panic in one of the pane
+// funcs and a short summary of the stack where it happened
+type TUIPanic struct {
+	Value interface{}
+	Stack []string
+}
+
+// Error returns panic message
+func (e *TUIPanic) Error() string {
+	return "panic: " + fmt.Sprint(e.Value)
+}
+
+// SetOnPanic attaches function that will be triggered when onDraw, onIterate,
+// a timer func, onKeyPress, onMouse or a func passed to Post panics. Panic is
+// recovered and, when it happened in a pane func, the error is shown inside
+// the pane. Function decides whether the interface should keep running
+// (returns true) or stop, in which case Run returns RESULT_ERROR. When there
+// is no function attached, interface keeps running.
+func (t *TUI) SetOnPanic(f func(*TUIPaneError) bool) {
+	t.onPanic = f
+}
+
+// GetPanic returns error of the recovered panic that happened in any of the
+// pane funcs or nil. Pane funcs are not called while it is set.
+func (p *TUIPane) GetPanic() *TUIPaneError {
+	return p.panic
+}
+
+// ClearPanic removes the panic error so pane funcs are called again and the
+// pane is redrawn
+func (p *TUIPane) ClearPanic() {
+	p.panic = nil
+	p.Invalidate()
+}
+
+// call calls pane func and recovers from the panic, which is then shown
+// inside the pane instead of its content
+func (p *TUIPane) call(f func(*TUIPane) int) (r int) {
+	defer func() {
+		if x := recover(); x != nil {
+			e := &TUIPaneError{
+				Pane: p,
+				Name: p.name,
+				Code: RESULT_ERROR,
+				Err:  &TUIPanic{Value: x, Stack: getStackSummary()},
+			}
+			p.panic = e
+			p.Invalidate()
+			if p.tui != nil {
+				p.tui.recovered(e)
+			}
+			r = RESULT_OK
+		}
+	}()
+	return f(p)
+}
+
+// callOnDraw calls onDraw of TUI and recovers from the panic
+func (t *TUI) callOnDraw() (r int) {
+	defer func() {
+		if x := recover(); x != nil {
+			t.recovered(&TUIPaneError{
+				Code: RESULT_ERROR,
+				Err:  &TUIPanic{Value: x, Stack: getStackSummary()},
+			})
+			r = RESULT_OK
+		}
+	}()
+	return t.onDraw(t)
+}
+
+// callEvent calls function posted to the UI goroutine (eg. keyboard or mouse
+// input) and recovers from the panic
+func (t *TUI) callEvent(f func()) {
+	defer func() {
+		if x := recover(); x != nil {
+			t.recovered(&TUIPaneError{
+				Code: RESULT_ERROR,
+				Err:  &TUIPanic{Value: x, Stack: getStackSummary()},
+			})
+		}
+	}()
+	f()
+}
+
+// recovered passes the error to onPanic func and stops the interface when
+// it says so
+func (t *TUI) recovered(e *TUIPaneError) {
+	if t.onPanic != nil && !t.onPanic(e) {
+		t.fail(e.Code, e)
+	}
+}
+
+// restoreOnPanic brings the terminal back to its normal state when the UI
+// goroutine panics outside of the funcs attached by the user and then panics
+// again so the program stops as it would normally do
+func (t *TUI) restoreOnPanic() {
+	if x := recover(); x != nil {
+		t.restoreTTY()
+		panic(x)
+	}
+}
+
+// drawPanic prints out the panic message and stack summary inside the pane
+func (p *TUIPane) drawPanic() {
+	lines := []string{"\u001b[31m" + fitString(p.panic.Err.Error(), p.GetContentWidth()) + "\u001b[0m"}
+	if e, ok := p.panic.Err.(*TUIPanic); ok {
+		for _, s := range e.Stack {
+			lines = append(lines, fitString(s, p.GetContentWidth()))
+		}
+	}
+	for i, l := range lines {
+		if i >= p.GetContentHeight() {
+			break
+		}
+		p.Write(0, i, l, false)
+	}
+}
+
+// getStackSummary returns functions with file names and lines where panic
+// happened. It has to be called in the deferred func that recovers.
+func getStackSummary() []string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(1, pc)
+	fs := runtime.CallersFrames(pc[:n])
+	var s []string
+	panicked := false
+	for len(s) < 5 {
+		f, more := fs.Next()
+		if strings.HasSuffix(f.Function, ".(*TUIPane).call") || strings.HasSuffix(f.Function, ".(*TUI).callOnDraw") || strings.HasSuffix(f.Function, ".(*TUI).callEvent") {
+			break
+		}
+		if panicked && !strings.HasPrefix(f.Function, "runtime.") {
+			fn := f.Function[strings.LastIndex(f.Function, "/")+1:]
+			s = append(s, fn+" "+filepath.Base(f.File)+":"+strconv.Itoa(f.Line))
+		}
+		if f.Function == "runtime.gopanic" {
+			panicked = true
+		}
+		if !more {
+			break
+		}
+	}
+	return s
+}
@@ -0,0 +1,25 @@
+package terminalui
+
+import "testing"
+
+func TestPanicInPostedFunc(t *testing.T) {
+	tui := NewTUI()
+	var got *TUIPaneError
+	tui.SetOnPanic(func(e *TUIPaneError) bool {
+		got = e
+		return true
+	})
+	tui.SetOnKeyPress(func(tui *TUI, b []byte) {
+		panic("key " + string(b))
+	})
+	tui.callEvent(func() {
+		tui.input([]byte("x"))
+	})
+	if got == nil {
+		t.Fatal("panic in onKeyPress was not recovered")
+	}
+	p, ok := got.Err.(*TUIPanic)
+	if !ok || p.Value != "key x" || len(p.Stack) == 0 {
+		t.Errorf("unexpected error %#v", got.Err)
+	}
+}
@@ -0,0 +1,122 @@
+package terminalui
+
+import (
+	"time"
+)
+
+// Invalidate marks pane to be redrawn. Main loop wakes up straight away and
+// redraws only the panes that were invalidated (clearing their area first),
+// so widgets do not have to wait for the next iteration. It is safe to call
+// from any goroutine. Nothing happens when TUI is not running.
+func (p *TUIPane) Invalidate() {
+	if p.tui == nil || !p.tui.isRunning() {
+		return
+	}
+	p.tui.eventsMu.Lock()
+	if p.tui.dirty == nil {
+		p.tui.dirty = map[*TUIPane]bool{}
+	}
+	p.tui.dirty[p] = true
+	p.tui.eventsMu.Unlock()
+	p.tui.wakeUp()
+}
+
+// GetMaxFPS returns maximum number of redraws per second
+func (t *TUI) GetMaxFPS() int {
+	return t.maxFPS
+}
+
+// SetMaxFPS limits how many times per second the interface (or invalidated
+// panes) can be redrawn. Redraws requested in between are merged into one.
+// Value of 0 means there is no limit.
+func (t *TUI) SetMaxFPS(n int) {
+	t.maxFPS = n
+}
+
+// render redraws the whole interface when Refresh was called, or otherwise
+// only the invalidated panes. When max frame rate would be exceeded, it
+// schedules main loop to wake up when next frame is allowed.
+func (t *TUI) render() {
+	t.eventsMu.Lock()
+	if (!t.redraw && len(t.dirty) == 0) || t.renderWait {
+		t.eventsMu.Unlock()
+		return
+	}
+	if d := t.getFrameDelay(); d > 0 {
+		t.renderWait = true
+		t.eventsMu.Unlock()
+		time.AfterFunc(d, func() {
+			t.eventsMu.Lock()
+			t.renderWait = false
+			t.eventsMu.Unlock()
+			t.wakeUp()
+		})
+		return
+	}
+	redraw, dirty := t.redraw, t.dirty
+	t.redraw = false
+	t.dirty = nil
+	t.eventsMu.Unlock()
+
+	t.lastRender = time.Now()
+	if redraw {
+		t.refreshSize()
+		t.layout()
+		t.draw()
+		return
+	}
+	covered := false
+	for p := range dirty {
+		if !t.isPaneShown(p) || p.hasDirtyAncestor(dirty) {
+			continue
+		}
+		p.clear()
+		p.Draw()
+		covered = covered || p.overlapped
+	}
+	if covered {
+		for _, o := range t.overlays {
+			o.draw()
+		}
+	}
+}
+
+// getFrameDelay returns how long main loop has to wait before drawing next
+// frame so the max frame rate is not exceeded
+func (t *TUI) getFrameDelay() time.Duration {
+	if t.maxFPS <= 0 {
+		return 0
+	}
+	return time.Until(t.lastRender.Add(time.Second / time.Duration(t.maxFPS)))
+}
+
+// isPaneShown returns true when pane is currently drawn on the terminal
+// window: it is inside the visible pane (or an overlay) and it is not in a
+// tab that is not active
+func (t *TUI) isPaneShown(p *TUIPane) bool {
+	if p.width <= 0 || p.height <= 0 {
+		return false
+	}
+	for ; ; p = p.parent {
+		if p == t.getVisiblePane() || t.getOverlayByPane(p) != nil {
+			return true
+		}
+		if p.parent == nil || p.parent.tooSmall {
+			return false
+		}
+		if p.parent.split == SPLIT_TABS && p.parent.tabs[p.parent.activeTab] != p {
+			return false
+		}
+	}
+}
+
+// hasDirtyAncestor returns true when any pane that contains p is going to be
+// redrawn as well
+func (p *TUIPane) hasDirtyAncestor(dirty map[*TUIPane]bool) bool {
+	for a := p.parent; a != nil; a = a.parent {
+		if dirty[a] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package terminalui
+
+import (
+	"testing"
+)
+
+func TestInvalidate(t *testing.T) {
+	tui := NewTUI()
+	tui.SetSize(40, 10)
+	a, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	x := b.AddTab("x")
+	y := b.AddTab("y")
+	draws := map[*TUIPane]int{}
+	for _, p := range []*TUIPane{a, x, y} {
+		p.SetOnDraw(func(p *TUIPane) int {
+			draws[p]++
+			return RESULT_OK
+		})
+	}
+	runTUI(t, tui)
+
+	// counts are read after the main loop renders what was requested
+	get := func() [3]int {
+		var c [3]int
+		tui.PostAndWait(func() {})
+		tui.PostAndWait(func() {
+			c = [3]int{draws[a], draws[x], draws[y]}
+		})
+		return c
+	}
+	// interface is drawn when Run starts and then when first main loop tick
+	// gets the size, so counting starts after that
+	for w := 0; w == 0; {
+		tui.PostAndWait(func() {
+			w = tui.w
+		})
+	}
+	want := get()
+	if want[0] == 0 || want[1] == 0 || want[2] != 0 {
+		t.Fatalf("first draw counts are %v", want)
+	}
+	for _, tt := range []struct {
+		name string
+		f    func()
+		add  [3]int
+	}{
+		{name: "pane", f: a.Invalidate, add: [3]int{1, 0, 0}},
+		{name: "many times", f: func() { x.Invalidate(); x.Invalidate() }, add: [3]int{0, 1, 0}},
+		{name: "hidden tab", f: y.Invalidate, add: [3]int{0, 0, 0}},
+		{name: "parent", f: func() { x.Invalidate(); b.Invalidate() }, add: [3]int{0, 1, 0}},
+		{name: "refresh", f: tui.Refresh, add: [3]int{1, 1, 0}},
+	} {
+		tt.f()
+		for i := range want {
+			want[i] += tt.add[i]
+		}
+		if got := get(); got != want {
+			t.Errorf("%s: draw counts are %v, want %v", tt.name, got, want)
+		}
+	}
+}
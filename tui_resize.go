@@ -0,0 +1,172 @@
+package terminalui
+
+import (
+	"math"
+)
+
+// ResizePane grows pane by d characters (or shrinks it when d is negative)
+// by moving the border of the closest split of specific type (SPLIT_V to
+// change width, SPLIT_H to change height). Split value keeps its unit and
+// minimal sizes of both sides are honoured, and nothing happens when the
+// pane cannot be resized. When TUI is running, pane is resized on the UI
+// goroutine so it can be called from any goroutine.
+func (t *TUI) ResizePane(p *TUIPane, split int, d int) {
+	t.queue(func() {
+		for c, s := p, p.parent; s != nil; c, s = s, s.parent {
+			if s.split != split {
+				continue
+			}
+			v1, _, tooSmall := s.getSplitValues()
+			if tooSmall {
+				return
+			}
+			if s.panes[0] == c {
+				s.setSplitSize(v1 + d)
+			} else {
+				s.setSplitSize(v1 - d)
+			}
+			return
+		}
+	})
+}
+
+// BindResizeKeys attaches keyboard shortcuts to resize focused pane:
+// ctrl+left and ctrl+right to change width, ctrl+up and ctrl+down to change
+// height
+func (t *TUI) BindResizeKeys() {
+	keys := map[string][2]int{
+		KEY_CTRL_LEFT:  {SPLIT_V, -1},
+		KEY_CTRL_RIGHT: {SPLIT_V, 1},
+		KEY_CTRL_UP:    {SPLIT_H, -1},
+		KEY_CTRL_DOWN:  {SPLIT_H, 1},
+	}
+	for k, v := range keys {
+		a := v
+		t.BindKey(k, func(t *TUI) {
+			if t.focus != nil {
+				t.ResizePane(t.focus, a[0], a[1])
+			}
+		})
+	}
+}
+
+// SetOnSplitResize attaches function that will be triggered when split
+// value is changed by ResizePane or by dragging a border with mouse. It
+// gets the split pane, its new split value and unit.
+func (t *TUI) SetOnSplitResize(f func(*TUIPane, int, int)) {
+	t.onSplitResize = f
+}
+
+// setSplitSize changes split value so the first pane gets v1 characters.
+// It returns false when any of the panes would be too small. It must be
+// called on the UI goroutine (see TUI.queue).
+func (p *TUIPane) setSplitSize(v1 int) bool {
+	base := p.width
+	if p.split == SPLIT_H {
+		base = p.height
+	}
+	v2 := base - v1
+	if v1 < p.panes[0].getRequiredSize(p.split) || v2 < p.panes[1].getRequiredSize(p.split) {
+		return false
+	}
+
+	ov1, _, _ := p.getSplitValues()
+	if v1 == ov1 {
+		return false
+	}
+
+	calc := v2
+	sign := 1
+	if p.splitValue < 0 {
+		calc = v1
+		sign = -1
+	}
+	old := p.splitValue
+	if p.splitUnit == UNIT_PERCENT {
+		// pick percentage that gives the closest size to the expected one
+		// but moves the border when characters changed
+		pct := int(math.Round(float64(calc) * 100 / float64(base)))
+		best, diff := int(math.Abs(float64(old))), base
+		for c := pct - 1; c <= pct+1; c++ {
+			r := int(float64(c) / 100 * float64(base))
+			if c < 1 || c > 99 || (c != best && r == p.getSplitFixedSize()) {
+				continue
+			}
+			if d := int(math.Abs(float64(r - calc))); d < diff {
+				best, diff = c, d
+			}
+		}
+		pct = best
+		p.splitValue = sign * pct
+	} else {
+		p.splitValue = sign * calc
+	}
+
+	n1, n2, tooSmall := p.getSplitValues()
+	if tooSmall || n1 == ov1 || n1 < p.panes[0].getRequiredSize(p.split) || n2 < p.panes[1].getRequiredSize(p.split) {
+		p.splitValue = old
+		return false
+	}
+	if p.tui.onSplitResize != nil {
+		p.tui.onSplitResize(p, p.splitValue, p.splitUnit)
+	}
+	return true
+}
+
+// getSplitFixedSize returns size in characters of the pane which size is
+// defined by the split value
+func (p *TUIPane) getSplitFixedSize() int {
+	v1, v2, _ := p.getSplitValues()
+	if p.splitValue < 0 {
+		return v1
+	}
+	return v2
+}
+
+// getRequiredSize returns required width for SPLIT_V or required height for
+// SPLIT_H
+func (p *TUIPane) getRequiredSize(split int) int {
+	if split == SPLIT_V {
+		return p.getRequiredWidth()
+	}
+	return p.getRequiredHeight()
+}
+
+// startDrag checks whether mouse was pressed on a border between two panes
+// and if so, remembers the split so its value changes when mouse moves
+func (t *TUI) startDrag(e *TUIMouseEvent) bool {
+	l := t.GetPaneAt(e.X, e.Y)
+	if l == nil || l.style == nil {
+		return false
+	}
+	for c, s := l, l.parent; s != nil; c, s = s, s.parent {
+		if s.split == SPLIT_V {
+			if (s.panes[1] == c && e.X == l.left && l.left == c.left && l.style.L() > 0) ||
+				(s.panes[0] == c && e.X == l.left+l.width-1 && l.left+l.width == c.left+c.width && l.style.R() > 0) {
+				t.drag = s
+				t.dragOffset = s.panes[1].left - e.X
+				return true
+			}
+		} else if s.split == SPLIT_H {
+			if (s.panes[1] == c && e.Y == l.top && l.top == c.top && l.style.T() > 0) ||
+				(s.panes[0] == c && e.Y == l.top+l.height-1 && l.top+l.height == c.top+c.height && l.style.B() > 0) {
+				t.drag = s
+				t.dragOffset = s.panes[1].top - e.Y
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// moveDrag changes split value of the dragged border
+func (t *TUI) moveDrag(e *TUIMouseEvent) {
+	s := t.drag
+	v1 := e.Y - s.top + t.dragOffset
+	if s.split == SPLIT_V {
+		v1 = e.X - s.left + t.dragOffset
+	}
+	t.queue(func() {
+		s.setSplitSize(v1)
+	})
+}
@@ -0,0 +1,59 @@
+//go:build !windows
+
+package terminalui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// watchResize makes main loop redraw the interface as soon as terminal
+// window gets resized instead of waiting for the next iteration. Signal is
+// sent only for the controlling terminal, so size of other ttys is checked
+// every half a second.
+func (t *TUI) watchResize() {
+	if t.tty == nil {
+		return
+	}
+	t.resizeSignal = make(chan os.Signal, 1)
+	signal.Notify(t.resizeSignal, syscall.SIGWINCH)
+	var tick <-chan time.Time
+	var ticker *time.Ticker
+	if t.tty != os.Stdin {
+		ticker = time.NewTicker(time.Millisecond * 500)
+		tick = ticker.C
+	}
+	go func(ch chan os.Signal) {
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+		w, h, _ := t.getSize()
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+				t.Refresh()
+			case <-tick:
+				nw, nh, err := t.getSize()
+				if err == nil && (nw != w || nh != h) {
+					w, h = nw, nh
+					t.Refresh()
+				}
+			}
+		}
+	}(t.resizeSignal)
+}
+
+// unwatchResize stops watching terminal window size
+func (t *TUI) unwatchResize() {
+	if t.resizeSignal == nil {
+		return
+	}
+	signal.Stop(t.resizeSignal)
+	close(t.resizeSignal)
+	t.resizeSignal = nil
+}
@@ -0,0 +1,10 @@
+package terminalui
+
+// watchResize does nothing on Windows where terminal size is checked only
+// in main loop, at most once a second
+func (t *TUI) watchResize() {
+}
+
+// unwatchResize does nothing on Windows
+func (t *TUI) unwatchResize() {
+}
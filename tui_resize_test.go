@@ -0,0 +1,94 @@
+package terminalui
+
+import (
+	"testing"
+)
+
+func TestSetSplitSize(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		split  int
+		value  int
+		unit   int
+		min    [2]int
+		v1     int
+		ok     bool
+		want   int
+		wantV1 int
+	}{
+		{name: "chars", split: SPLIT_V, value: 20, unit: UNIT_CHAR, v1: 25, ok: true, want: 15, wantV1: 25},
+		{name: "chars left", split: SPLIT_V, value: -10, unit: UNIT_CHAR, v1: 15, ok: true, want: -15, wantV1: 15},
+		{name: "chars height", split: SPLIT_H, value: 4, unit: UNIT_CHAR, v1: 3, ok: true, want: 7, wantV1: 3},
+		{name: "percent", split: SPLIT_V, value: 50, unit: UNIT_PERCENT, v1: 30, ok: true, want: 25, wantV1: 30},
+		{name: "percent left", split: SPLIT_V, value: -50, unit: UNIT_PERCENT, v1: 10, ok: true, want: -25, wantV1: 10},
+		{name: "percent one char", split: SPLIT_V, value: 50, unit: UNIT_PERCENT, v1: 21, ok: true, want: 48, wantV1: 21},
+		{name: "percent one char left", split: SPLIT_V, value: -50, unit: UNIT_PERCENT, v1: 19, ok: true, want: -48, wantV1: 19},
+		{name: "percent height", split: SPLIT_H, value: 50, unit: UNIT_PERCENT, v1: 3, ok: true, want: 70, wantV1: 3},
+		{name: "same size", split: SPLIT_V, value: 20, unit: UNIT_CHAR, v1: 20, want: 20, wantV1: 20},
+		{name: "first too small", split: SPLIT_V, value: 20, unit: UNIT_CHAR, min: [2]int{15, 0}, v1: 14, want: 20, wantV1: 20},
+		{name: "first minimal", split: SPLIT_V, value: 20, unit: UNIT_CHAR, min: [2]int{15, 0}, v1: 15, ok: true, want: 25, wantV1: 15},
+		{name: "second too small", split: SPLIT_V, value: -20, unit: UNIT_CHAR, min: [2]int{0, 15}, v1: 26, want: -20, wantV1: 20},
+		{name: "second minimal", split: SPLIT_V, value: -20, unit: UNIT_CHAR, min: [2]int{0, 15}, v1: 25, ok: true, want: -25, wantV1: 25},
+		{name: "second too small percent", split: SPLIT_H, value: 50, unit: UNIT_PERCENT, min: [2]int{0, 4}, v1: 7, want: 50, wantV1: 5},
+		{name: "whole width", split: SPLIT_V, value: 20, unit: UNIT_CHAR, v1: 40, want: 20, wantV1: 20},
+		{name: "zero", split: SPLIT_V, value: 20, unit: UNIT_CHAR, v1: 0, want: 20, wantV1: 20},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tui := newTreeTUI(40, 10)
+			var called [2]int
+			tui.SetOnSplitResize(func(p *TUIPane, v int, u int) {
+				called = [2]int{v, u}
+			})
+			p := tui.GetPane()
+			a, b := p.Split(tt.split, tt.value, tt.unit)
+			if tt.split == SPLIT_V {
+				a.SetMinWidth(tt.min[0])
+				b.SetMinWidth(tt.min[1])
+			} else {
+				a.SetMinHeight(tt.min[0])
+				b.SetMinHeight(tt.min[1])
+			}
+			tui.layout()
+			if ok := p.setSplitSize(tt.v1); ok != tt.ok {
+				t.Errorf("got %v", ok)
+			}
+			if p.splitValue != tt.want || p.splitUnit != tt.unit {
+				t.Errorf("got split value %d %d, want %d %d", p.splitValue, p.splitUnit, tt.want, tt.unit)
+			}
+			if v1, _, _ := p.getSplitValues(); v1 != tt.wantV1 {
+				t.Errorf("first pane size is %d, want %d", v1, tt.wantV1)
+			}
+			if tt.ok && called != [2]int{tt.want, tt.unit} {
+				t.Errorf("onSplitResize got %v", called)
+			} else if !tt.ok && called != [2]int{} {
+				t.Errorf("onSplitResize was called")
+			}
+		})
+	}
+}
+
+func TestResizePane(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a, b := tui.GetPane().SplitVertically(20, UNIT_CHAR)
+	c, d := b.SplitHorizontally(-5, UNIT_CHAR)
+	tui.layout()
+	for _, tt := range []struct {
+		name  string
+		p     *TUIPane
+		split int
+		d     int
+		want  [2]int
+	}{
+		{name: "left grows", p: a, split: SPLIT_V, d: 2, want: [2]int{18, -5}},
+		{name: "right grows", p: c, split: SPLIT_V, d: 3, want: [2]int{21, -5}},
+		{name: "top shrinks", p: c, split: SPLIT_H, d: -1, want: [2]int{21, -4}},
+		{name: "bottom grows", p: d, split: SPLIT_H, d: 2, want: [2]int{21, -2}},
+		{name: "no split", p: a, split: SPLIT_H, d: 1, want: [2]int{21, -2}},
+	} {
+		tui.ResizePane(tt.p, tt.split, tt.d)
+		tui.layout()
+		if got := [2]int{tui.GetPane().splitValue, b.splitValue}; got != tt.want {
+			t.Errorf("%s: got split values %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
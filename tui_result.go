@@ -0,0 +1,110 @@
+package terminalui
+
+import (
+	"strconv"
+)
+
+// Values returned by onDraw and onIterate funcs (of both panes and TUI).
+// RESULT_OK means everything went fine (0 is treated the same way).
+// RESULT_REDRAW returned from onIterate makes the pane redrawn. RESULT_EXIT
+// stops the interface and makes Run return 0. Negative value means an error
+// which stops the interface and makes Run return that value.
+const RESULT_OK = 1
+const RESULT_REDRAW = 2
+const RESULT_EXIT = 3
+const RESULT_ERROR = -1
+
+// TUIPaneError is an error that stopped the interface. It has the pane which
+// func failed (nil when it was TUI's onDraw), the pane name, returned code
+// and the underlying error when there was one.
+type TUIPaneError struct {
+	Pane *TUIPane
+	Name string
+	Code int
+	Err  error
+}
+
+// Error returns error message with pane name
+func (e *TUIPaneError) Error() string {
+	s := "onDraw of TUI"
+	if e.Pane != nil {
+		s = "pane '" + e.Name + "'"
+	}
+	if e.Err != nil {
+		return s + ": " + e.Err.Error()
+	}
+	return s + " returned " + strconv.Itoa(e.Code)
+}
+
+// Unwrap returns the underlying error
+func (e *TUIPaneError) Unwrap() error {
+	return e.Err
+}
+
+// GetError returns error that stopped the interface or nil
+func (t *TUI) GetError() error {
+	return t.err
+}
+
+// handleResult acts on value returned by onDraw or onIterate of the pane and
+// passes it on
+func (p *TUIPane) handleResult(r int, iterate bool) int {
+	if p.tui == nil {
+		return r
+	}
+	switch {
+	case r < 0:
+		p.tui.fail(r, &TUIPaneError{Pane: p, Name: p.name, Code: r})
+	case r == RESULT_EXIT:
+		p.tui.quit(0)
+	case r == RESULT_REDRAW && iterate:
+		p.Invalidate()
+	}
+	return r
+}
+
+// handleResult acts on value returned by onDraw of TUI
+func (t *TUI) handleResult(r int) {
+	switch {
+	case r < 0:
+		t.fail(r, &TUIPaneError{Code: r})
+	case r == RESULT_EXIT:
+		t.quit(0)
+	}
+}
+
+// mergeResults returns the more important of two results: an error, then
+// exit, and RESULT_OK otherwise
+func mergeResults(a int, b int) int {
+	if a < 0 {
+		return a
+	}
+	if b < 0 {
+		return b
+	}
+	if a == RESULT_EXIT || b == RESULT_EXIT {
+		return RESULT_EXIT
+	}
+	return RESULT_OK
+}
+
+// quit makes main loop stop and Run return the code, unless it is already
+// stopping
+func (t *TUI) quit(code int) {
+	if t.stopping {
+		return
+	}
+	t.stopping = true
+	t.exitCode = code
+}
+
+// fail makes main loop stop and Run return the error code. Only the first
+// error is kept.
+func (t *TUI) fail(code int, err error) {
+	if t.err != nil {
+		return
+	}
+	t.err = err
+	t.stopping = true
+	t.exitCode = code
+}
@@ -0,0 +1,130 @@
+package terminalui
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+// runUntilStopped runs TUI and returns what Run returned once the interface
+// stops by itself
+func runUntilStopped(t *testing.T, tui *TUI) int {
+	r, w := io.Pipe()
+	defer w.Close()
+	tui.SetInput(r)
+	tui.SetOutput(io.Discard)
+	tui.SetSize(40, 10)
+	done := make(chan int)
+	go func() {
+		done <- tui.Run(nil, nil)
+	}()
+	select {
+	case code := <-done:
+		return code
+	case <-time.After(5 * time.Second):
+		tui.Stop(0)
+		<-done
+		t.Fatal("interface did not stop")
+	}
+	return 0
+}
+
+func TestResultCodes(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		set     func(tui *TUI)
+		code    int
+		message string
+	}{
+		{
+			name: "exit from draw",
+			set: func(tui *TUI) {
+				tui.GetPane().SetOnDraw(func(p *TUIPane) int { return RESULT_EXIT })
+			},
+			code: 0,
+		},
+		{
+			name: "error from iterate",
+			set: func(tui *TUI) {
+				_, b := tui.GetPane().SplitVertically(50, UNIT_PERCENT, "a", "b")
+				b.SetOnIterate(func(p *TUIPane) int { return -5 })
+			},
+			code:    -5,
+			message: "pane 'b' returned -5",
+		},
+		{
+			name: "error from TUI draw",
+			set: func(tui *TUI) {
+				tui.SetOnDraw(func(t *TUI) int { return RESULT_ERROR })
+			},
+			code:    RESULT_ERROR,
+			message: "onDraw of TUI returned -1",
+		},
+		{
+			name: "redraw from iterate",
+			set: func(tui *TUI) {
+				draws := 0
+				p := tui.GetPane()
+				p.SetIterateInterval(1)
+				p.SetOnDraw(func(p *TUIPane) int {
+					draws++
+					return 0
+				})
+				p.SetOnIterate(func(p *TUIPane) int {
+					if draws >= 3 {
+						return RESULT_EXIT
+					}
+					return RESULT_REDRAW
+				})
+			},
+			code: 0,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tui := NewTUI()
+			tt.set(tui)
+			if code := runUntilStopped(t, tui); code != tt.code {
+				t.Errorf("Run returned %d, want %d", code, tt.code)
+			}
+			err := tui.GetError()
+			if tt.message == "" {
+				if err != nil {
+					t.Errorf("got error %v", err)
+				}
+				return
+			}
+			var pe *TUIPaneError
+			if !errors.As(err, &pe) || pe.Code != tt.code || err.Error() != tt.message {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
+
+func TestPaneErrorWraps(t *testing.T) {
+	e := errors.New("broken")
+	err := &TUIPaneError{Pane: NewTUIPane("p", nil), Name: "p", Code: RESULT_ERROR, Err: e}
+	if !errors.Is(err, e) || err.Error() != "pane 'p': broken" {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	for _, tt := range []struct {
+		a    int
+		b    int
+		want int
+	}{
+		{a: RESULT_OK, b: RESULT_OK, want: RESULT_OK},
+		{a: 0, b: RESULT_REDRAW, want: RESULT_OK},
+		{a: RESULT_EXIT, b: RESULT_OK, want: RESULT_EXIT},
+		{a: RESULT_OK, b: RESULT_EXIT, want: RESULT_EXIT},
+		{a: RESULT_EXIT, b: -2, want: -2},
+		{a: -3, b: -2, want: -3},
+	} {
+		if got := mergeResults(tt.a, tt.b); got != tt.want {
+			t.Errorf("%d, %d: got %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,782 @@
+package terminalui
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// states of the escape sequence parser of TUIScreen
+const screenGround = 0
+const screenEscape = 1
+const screenCSI = 2
+const screenString = 3
+const screenStringEscape = 4
+const screenCharset = 5
+
+// decGraphics maps characters to line drawing ones when DEC special
+// graphics character set is selected (eg. with "\u001b(0")
+var decGraphics = map[rune]rune{
+	'`': '◆', 'a': '▒', 'f': '°', 'g': '±', 'j': '┘', 'k': '┐', 'l': '┌', 'm': '└',
+	'n': '┼', 'o': '⎺', 'p': '⎻', 'q': '─', 'r': '⎼', 's': '⎽', 't': '├', 'u': '┤',
+	'v': '┴', 'w': '┬', 'x': '│', 'y': '≤', 'z': '≥', '{': 'π', '|': '≠', '}': '£',
+	'~': '·',
+}
+
+// TUIScreenCell is a single character cell of TUIScreen. Wide character
+// takes two cells and Rune of the second one is 0.
+type TUIScreenCell struct {
+	Rune  rune
+	Style TUICellStyle
+}
+
+// TUIScreen is a virtual terminal screen. Output of a program (with VT100
+// and xterm escape sequences moving the cursor, setting scroll region,
+// switching to alternate screen, inserting, deleting and erasing, and
+// setting colors) written to it is turned into a grid of character cells
+// which can be read or drawn inside a pane. Wide characters (eg. CJK) take
+// two cells. Writing is safe from many goroutines.
+type TUIScreen struct {
+	width        int
+	height       int
+	cells        [][]TUIScreenCell
+	x            int
+	y            int
+	saved        tuiScreenCursor
+	style        TUICellStyle
+	wrapNext     bool
+	top          int
+	bottom       int
+	alt          bool
+	main         [][]TUIScreenCell
+	mainSaved    tuiScreenCursor
+	hideCursor   bool
+	appCursor    bool
+	noAutowrap   bool
+	insert       bool
+	charsets     [2]bool
+	shift        int
+	charsetIndex int
+	last         rune
+	state        int
+	params       []byte
+	rune         []byte
+	reply        []byte
+	onReply      func([]byte)
+	mu           sync.Mutex
+}
+
+// tuiScreenCursor is cursor position and style saved with "\u001b7"
+type tuiScreenCursor struct {
+	x     int
+	y     int
+	style TUICellStyle
+}
+
+// GetWidth returns screen width
+func (s *TUIScreen) GetWidth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.width
+}
+
+// GetHeight returns screen height
+func (s *TUIScreen) GetHeight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.height
+}
+
+// GetCursor returns cursor position
+func (s *TUIScreen) GetCursor() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.x, s.y
+}
+
+// IsCursorVisible returns false when program has hidden the cursor
+func (s *TUIScreen) IsCursorVisible() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.hideCursor
+}
+
+// IsAlternate returns true when program has switched to alternate screen
+// (eg. a full screen editor is running)
+func (s *TUIScreen) IsAlternate() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.alt
+}
+
+// IsAppCursor returns true when program has requested application cursor
+// keys mode, in which arrow keys should be sent as "\u001bOA" instead of
+// "\u001b[A" etc.
+func (s *TUIScreen) IsAppCursor() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.appCursor
+}
+
+// GetCell returns cell at specific position
+func (s *TUIScreen) GetCell(x int, y int) TUIScreenCell {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return s.blank()
+	}
+	return s.cells[y][x]
+}
+
+// GetLine returns text of a line without colors and trailing spaces
+func (s *TUIScreen) GetLine(y int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if y < 0 || y >= s.height {
+		return ""
+	}
+	var b strings.Builder
+	for x := range s.cells[y] {
+		if r := getCellRune(s.cells[y], x); r != 0 {
+			b.WriteRune(r)
+		}
+	}
+	return strings.TrimRight(b.String(), " ")
+}
+
+// String returns text of all the lines without colors
+func (s *TUIScreen) String() string {
+	lines := make([]string, s.GetHeight())
+	for y := range lines {
+		lines[y] = s.GetLine(y)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SetOnReply attaches function that gets responses to queries sent by the
+// program (eg. cursor position report), which should be written back to
+// the program's input
+func (s *TUIScreen) SetOnReply(f func([]byte)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReply = f
+}
+
+// Resize changes screen size keeping the content that fits
+func (s *TUIScreen) Resize(w int, h int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resize(w, h)
+}
+
+// Reset clears the screen, moves cursor to the top left corner and resets
+// all the modes
+func (s *TUIScreen) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+	s.state = screenGround
+}
+
+// Write interprets output of a program. Escape sequences can be split
+// between many writes.
+func (s *TUIScreen) Write(b []byte) (int, error) {
+	s.mu.Lock()
+	for _, c := range b {
+		s.writeByte(c)
+	}
+	reply, f := s.reply, s.onReply
+	s.reply = nil
+	s.mu.Unlock()
+	if len(reply) > 0 && f != nil {
+		f(reply)
+	}
+	return len(b), nil
+}
+
+// Draw prints the screen inside pane content area. Screen is clipped when
+// it is bigger than the pane.
+func (s *TUIScreen) Draw(p *TUIPane) {
+	s.draw(p, false)
+}
+
+// draw prints the screen inside pane content area, optionally with the
+// cursor shown as a reversed cell
+func (s *TUIScreen) draw(p *TUIPane, cursor bool) {
+	cw, ch := p.GetContentWidth(), p.GetContentHeight()
+	for y := 0; y < ch; y++ {
+		p.Write(0, y, s.getStyledLine(y, cw, cursor), false)
+	}
+}
+
+// getStyledLine returns line with escape sequences setting colors, cut or
+// padded to w columns. Wide character that does not fit is replaced with
+// a space.
+func (s *TUIScreen) getStyledLine(y int, w int, cursor bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var b strings.Builder
+	cur := NewTUICellStyle()
+	for x := 0; x < w; x++ {
+		c := s.blank()
+		if y < s.height && x < s.width {
+			c = s.cells[y][x]
+			c.Rune = getCellRune(s.cells[y], x)
+		}
+		if c.Rune == 0 {
+			// second column of wide character
+			continue
+		}
+		if x == w-1 && getRuneWidth(c.Rune) == 2 {
+			c.Rune = ' '
+		}
+		if cursor && !s.hideCursor && x == s.x && y == s.y {
+			c.Style.Reverse = !c.Style.Reverse
+		}
+		if c.Style != cur {
+			b.WriteString(c.Style.SGR())
+			cur = c.Style
+		}
+		b.WriteRune(c.Rune)
+	}
+	if !cur.IsDefault() {
+		b.WriteString("\u001b[0m")
+	}
+	return b.String()
+}
+
+// writeByte passes byte to the escape sequence parser
+func (s *TUIScreen) writeByte(c byte) {
+	switch s.state {
+	case screenGround:
+		if len(s.rune) > 0 || c >= 0x80 {
+			s.rune = append(s.rune, c)
+			if utf8.FullRune(s.rune) {
+				r, _ := utf8.DecodeRune(s.rune)
+				s.rune = s.rune[:0]
+				s.put(r)
+			}
+			return
+		}
+		if c == 0x1b {
+			s.state = screenEscape
+		} else if c < 0x20 {
+			s.control(c)
+		} else if c != 0x7f {
+			s.put(rune(c))
+		}
+	case screenEscape:
+		s.state = screenGround
+		s.escape(c)
+	case screenCSI:
+		if c >= 0x40 && c <= 0x7e {
+			s.state = screenGround
+			s.csi(c, string(s.params))
+		} else if c >= 0x20 {
+			s.params = append(s.params, c)
+		} else if c == 0x1b {
+			s.state = screenEscape
+		} else {
+			s.control(c)
+		}
+	case screenString:
+		// OSC, DCS and similar strings end with BEL or ESC \
+		if c == 0x07 {
+			s.state = screenGround
+		} else if c == 0x1b {
+			s.state = screenStringEscape
+		}
+	case screenStringEscape:
+		s.state = screenGround
+	case screenCharset:
+		s.state = screenGround
+		if s.charsetIndex < len(s.charsets) {
+			s.charsets[s.charsetIndex] = c == '0'
+		}
+	}
+}
+
+// control handles control characters
+func (s *TUIScreen) control(c byte) {
+	switch c {
+	case '\r':
+		s.x = 0
+		s.wrapNext = false
+	case '\n', '\v', '\f':
+		s.lineFeed()
+	case '\b':
+		if s.x > 0 {
+			s.x--
+		}
+		s.wrapNext = false
+	case '\t':
+		s.x = min((s.x/8+1)*8, max(s.width-1, 0))
+	case 0x0e:
+		s.shift = 1
+	case 0x0f:
+		s.shift = 0
+	}
+}
+
+// escape handles byte that follows escape character
+func (s *TUIScreen) escape(c byte) {
+	switch c {
+	case '[':
+		s.state = screenCSI
+		s.params = s.params[:0]
+	case ']', 'P', '_', '^', 'X':
+		s.state = screenString
+	case '(', ')':
+		s.state = screenCharset
+		s.charsetIndex = int(c - '(')
+	case '*', '+':
+		// G2 and G3 are not used
+		s.state = screenCharset
+		s.charsetIndex = int(c - '(')
+	case '7':
+		s.saved = tuiScreenCursor{s.x, s.y, s.style}
+	case '8':
+		s.moveTo(s.saved.x, s.saved.y)
+		s.style = s.saved.style
+	case 'D':
+		s.lineFeed()
+	case 'E':
+		s.x = 0
+		s.lineFeed()
+	case 'M':
+		s.reverseIndex()
+	case 'c':
+		s.reset()
+	}
+}
+
+// csi handles control sequence with its parameters and final byte
+func (s *TUIScreen) csi(f byte, params string) {
+	if strings.HasPrefix(params, "?") {
+		s.privateMode(f, params[1:])
+		return
+	}
+	if strings.HasPrefix(params, ">") {
+		if f == 'c' {
+			s.reply = append(s.reply, "\u001b[>0;0;0c"...)
+		}
+		return
+	}
+	// sequences with intermediate bytes (eg. "\u001b[2 q" setting cursor
+	// shape) are not supported
+	if strings.IndexFunc(params, func(r rune) bool { return r < '0' || r > ';' }) != -1 {
+		return
+	}
+	if f == 'm' {
+		s.style = s.style.applySGR(params)
+		return
+	}
+	ps := strings.Split(params, ";")
+	arg := func(i int, d int) int {
+		if i >= len(ps) {
+			return d
+		}
+		n, err := strconv.Atoi(ps[i])
+		if err != nil || n == 0 {
+			return d
+		}
+		return n
+	}
+	switch f {
+	case 'A':
+		s.moveTo(s.x, s.y-arg(0, 1))
+	case 'B', 'e':
+		s.moveTo(s.x, s.y+arg(0, 1))
+	case 'C', 'a':
+		s.moveTo(s.x+arg(0, 1), s.y)
+	case 'D':
+		s.moveTo(s.x-arg(0, 1), s.y)
+	case 'E':
+		s.moveTo(0, s.y+arg(0, 1))
+	case 'F':
+		s.moveTo(0, s.y-arg(0, 1))
+	case 'G', '`':
+		s.moveTo(arg(0, 1)-1, s.y)
+	case 'd':
+		s.moveTo(s.x, arg(0, 1)-1)
+	case 'H', 'f':
+		s.moveTo(arg(1, 1)-1, arg(0, 1)-1)
+	case 'J':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseRect(s.x, s.y, s.width, s.y+1)
+			s.eraseRect(0, s.y+1, s.width, s.height)
+		case 1:
+			s.eraseRect(0, 0, s.width, s.y)
+			s.eraseRect(0, s.y, s.x+1, s.y+1)
+		case 2, 3:
+			s.eraseRect(0, 0, s.width, s.height)
+		}
+	case 'K':
+		switch arg(0, 0) {
+		case 0:
+			s.eraseRect(s.x, s.y, s.width, s.y+1)
+		case 1:
+			s.eraseRect(0, s.y, s.x+1, s.y+1)
+		case 2:
+			s.eraseRect(0, s.y, s.width, s.y+1)
+		}
+	case 'L':
+		if s.y >= s.top && s.y <= s.bottom {
+			s.scrollDown(s.y, s.bottom, arg(0, 1))
+		}
+	case 'M':
+		if s.y >= s.top && s.y <= s.bottom {
+			s.scrollUp(s.y, s.bottom, arg(0, 1))
+		}
+	case '@':
+		s.insertBlanks(arg(0, 1))
+	case 'P':
+		s.deleteChars(arg(0, 1))
+	case 'X':
+		s.eraseRect(s.x, s.y, s.x+arg(0, 1), s.y+1)
+	case 'S':
+		s.scrollUp(s.top, s.bottom, arg(0, 1))
+	case 'T':
+		s.scrollDown(s.top, s.bottom, arg(0, 1))
+	case 'b':
+		// count is limited to the screen size, which is enough to fill it
+		if s.last != 0 {
+			for i := min(arg(0, 1), s.width*s.height); i > 0; i-- {
+				s.put(s.last)
+			}
+		}
+	case 'r':
+		t, b := arg(0, 1)-1, arg(1, s.height)-1
+		if t < b && b < s.height {
+			s.top, s.bottom = t, b
+			s.moveTo(0, 0)
+		}
+	case 's':
+		s.saved = tuiScreenCursor{s.x, s.y, s.style}
+	case 'u':
+		s.moveTo(s.saved.x, s.saved.y)
+	case 'h', 'l':
+		if arg(0, 0) == 4 {
+			s.insert = f == 'h'
+		}
+	case 'n':
+		switch arg(0, 0) {
+		case 5:
+			s.reply = append(s.reply, "\u001b[0n"...)
+		case 6:
+			s.reply = append(s.reply, "\u001b["+strconv.Itoa(s.y+1)+";"+strconv.Itoa(s.x+1)+"R"...)
+		}
+	case 'c':
+		s.reply = append(s.reply, "\u001b[?1;2c"...)
+	}
+}
+
+// privateMode handles DEC private modes set with "\u001b[?...h" and reset
+// with "\u001b[?...l"
+func (s *TUIScreen) privateMode(f byte, params string) {
+	if f != 'h' && f != 'l' {
+		return
+	}
+	on := f == 'h'
+	for _, p := range strings.Split(params, ";") {
+		switch p {
+		case "1":
+			s.appCursor = on
+		case "7":
+			s.noAutowrap = !on
+		case "25":
+			s.hideCursor = !on
+		case "47", "1047":
+			s.setAlternate(on, false)
+		case "1049":
+			s.setAlternate(on, true)
+		}
+	}
+}
+
+// setAlternate switches to alternate screen (which is cleared) or back to
+// the main one, optionally saving and restoring cursor
+func (s *TUIScreen) setAlternate(on bool, cursor bool) {
+	if on == s.alt {
+		return
+	}
+	s.alt = on
+	if on {
+		if cursor {
+			s.mainSaved = tuiScreenCursor{s.x, s.y, s.style}
+		}
+		s.main = s.cells
+		s.cells = newScreenCells(s.width, s.height, s.blank())
+		return
+	}
+	s.cells = s.main
+	s.main = nil
+	if cursor {
+		s.moveTo(s.mainSaved.x, s.mainSaved.y)
+		s.style = s.mainSaved.style
+	}
+}
+
+// put prints character at the cursor position and moves the cursor. When
+// the last column is reached, next character goes to the next line (unless
+// autowrap is disabled). Wide character takes two cells and is moved to
+// the next line when there is only one column left. Zero width characters
+// (eg. combining marks) are skipped.
+func (s *TUIScreen) put(r rune) {
+	if s.width == 0 || s.height == 0 {
+		return
+	}
+	if s.charsets[s.shift] {
+		if g, ok := decGraphics[r]; ok {
+			r = g
+		}
+	}
+	rw := getRuneWidth(r)
+	if rw == 0 {
+		return
+	}
+	s.last = r
+	if s.wrapNext {
+		s.x = 0
+		s.lineFeed()
+	}
+	if rw == 2 && s.x == s.width-1 {
+		if s.noAutowrap || s.width < 2 {
+			return
+		}
+		s.eraseWide(s.x, s.y)
+		s.cells[s.y][s.x] = s.blank()
+		s.x = 0
+		s.lineFeed()
+	}
+	if s.insert {
+		s.insertBlanks(rw)
+	}
+	for i := 0; i < rw; i++ {
+		s.eraseWide(s.x+i, s.y)
+	}
+	s.cells[s.y][s.x] = TUIScreenCell{Rune: r, Style: s.style}
+	if rw == 2 {
+		s.cells[s.y][s.x+1] = TUIScreenCell{Style: s.style}
+	}
+	if s.x+rw < s.width {
+		s.x += rw
+	} else if !s.noAutowrap {
+		s.x = s.width - 1
+		s.wrapNext = true
+	}
+}
+
+// eraseWide clears the other half of wide character when the cell is a
+// part of one, before the cell is overwritten
+func (s *TUIScreen) eraseWide(x int, y int) {
+	l := s.cells[y]
+	if l[x].Rune == 0 && x > 0 {
+		l[x-1] = s.blank()
+	}
+	if l[x].Rune != 0 && x+1 < s.width && l[x+1].Rune == 0 {
+		l[x+1] = s.blank()
+	}
+}
+
+// lineFeed moves cursor to the next line and scrolls the scroll region
+// when cursor is on its last line
+func (s *TUIScreen) lineFeed() {
+	s.wrapNext = false
+	if s.height == 0 {
+		return
+	}
+	if s.y == s.bottom {
+		s.scrollUp(s.top, s.bottom, 1)
+		return
+	}
+	if s.y < s.height-1 {
+		s.y++
+	}
+}
+
+// reverseIndex moves cursor to the previous line and scrolls the scroll
+// region down when cursor is on its first line
+func (s *TUIScreen) reverseIndex() {
+	s.wrapNext = false
+	if s.height == 0 {
+		return
+	}
+	if s.y == s.top {
+		s.scrollDown(s.top, s.bottom, 1)
+		return
+	}
+	if s.y > 0 {
+		s.y--
+	}
+}
+
+// scrollUp moves lines from top to bottom (inclusive) up by n and adds
+// empty ones at the bottom
+func (s *TUIScreen) scrollUp(top int, bottom int, n int) {
+	if s.height == 0 {
+		return
+	}
+	n = min(n, bottom-top+1)
+	for i := 0; i < n; i++ {
+		l := s.cells[top]
+		copy(s.cells[top:bottom], s.cells[top+1:bottom+1])
+		s.cells[bottom] = l
+		s.eraseRect(0, bottom, s.width, bottom+1)
+	}
+}
+
+// scrollDown moves lines from top to bottom (inclusive) down by n and adds
+// empty ones at the top
+func (s *TUIScreen) scrollDown(top int, bottom int, n int) {
+	if s.height == 0 {
+		return
+	}
+	n = min(n, bottom-top+1)
+	for i := 0; i < n; i++ {
+		l := s.cells[bottom]
+		copy(s.cells[top+1:bottom+1], s.cells[top:bottom])
+		s.cells[top] = l
+		s.eraseRect(0, top, s.width, top+1)
+	}
+}
+
+// insertBlanks moves characters from the cursor to the right by n, adding
+// empty cells
+func (s *TUIScreen) insertBlanks(n int) {
+	if s.height == 0 {
+		return
+	}
+	l := s.cells[s.y]
+	n = min(n, s.width-s.x)
+	copy(l[s.x+n:], l[s.x:])
+	s.eraseRect(s.x, s.y, s.x+n, s.y+1)
+}
+
+// deleteChars removes n characters at the cursor moving the rest of the
+// line to the left
+func (s *TUIScreen) deleteChars(n int) {
+	if s.height == 0 {
+		return
+	}
+	l := s.cells[s.y]
+	n = min(n, s.width-s.x)
+	copy(l[s.x:], l[s.x+n:])
+	s.eraseRect(s.width-n, s.y, s.width, s.y+1)
+}
+
+// moveTo moves cursor to the position within the screen
+func (s *TUIScreen) moveTo(x int, y int) {
+	s.x = min(max(x, 0), max(s.width-1, 0))
+	s.y = min(max(y, 0), max(s.height-1, 0))
+	s.wrapNext = false
+}
+
+// eraseRect clears cells from x1,y1 to x2,y2 (exclusive)
+func (s *TUIScreen) eraseRect(x1 int, y1 int, x2 int, y2 int) {
+	for y := max(y1, 0); y < y2 && y < s.height; y++ {
+		for x := max(x1, 0); x < x2 && x < s.width; x++ {
+			s.cells[y][x] = s.blank()
+		}
+	}
+}
+
+// blank returns empty cell with current background color
+func (s *TUIScreen) blank() TUIScreenCell {
+	st := NewTUICellStyle()
+	st.BG = s.style.BG
+	return TUIScreenCell{Rune: ' ', Style: st}
+}
+
+// reset clears the screen and resets all the modes
+func (s *TUIScreen) reset() {
+	if s.alt {
+		s.cells = s.main
+		s.main = nil
+	}
+	s.alt = false
+	s.x, s.y = 0, 0
+	s.saved = tuiScreenCursor{style: NewTUICellStyle()}
+	s.style = NewTUICellStyle()
+	s.wrapNext = false
+	s.top, s.bottom = 0, max(s.height-1, 0)
+	s.hideCursor, s.appCursor, s.noAutowrap, s.insert = false, false, false, false
+	s.charsets = [2]bool{}
+	s.shift = 0
+	s.last = 0
+	s.eraseRect(0, 0, s.width, s.height)
+}
+
+// resize changes screen size keeping the content that fits. Scroll region
+// is reset to the whole screen, unless the size has not changed.
+func (s *TUIScreen) resize(w int, h int) {
+	w, h = max(w, 0), max(h, 0)
+	if w == s.width && h == s.height && s.cells != nil {
+		return
+	}
+	bl := TUIScreenCell{Rune: ' ', Style: NewTUICellStyle()}
+	s.cells = resizeScreenCells(s.cells, w, h, bl)
+	if s.alt {
+		s.main = resizeScreenCells(s.main, w, h, bl)
+	}
+	s.width = w
+	s.height = h
+	s.top = 0
+	s.bottom = max(h-1, 0)
+	s.moveTo(s.x, s.y)
+}
+
+// getCellRune returns character shown in the cell of the line: 0 for the
+// second column of wide character, and space for the half of wide
+// character that was left when the other one was overwritten
+func getCellRune(l []TUIScreenCell, x int) rune {
+	r := l[x].Rune
+	if r == 0 {
+		if x > 0 && getRuneWidth(l[x-1].Rune) == 2 {
+			return 0
+		}
+		return ' '
+	}
+	if getRuneWidth(r) == 2 && (x+1 >= len(l) || l[x+1].Rune != 0) {
+		return ' '
+	}
+	return r
+}
+
+// newScreenCells returns w x h cells filled with c
+func newScreenCells(w int, h int, c TUIScreenCell) [][]TUIScreenCell {
+	return resizeScreenCells(nil, w, h, c)
+}
+
+// resizeScreenCells returns w x h cells with content of cells, and new
+// cells filled with c. Lines and cells that do not fit are kept beyond the
+// length of the slices, so they are back when the screen gets bigger again.
+func resizeScreenCells(cells [][]TUIScreenCell, w int, h int, c TUIScreenCell) [][]TUIScreenCell {
+	if h <= cap(cells) {
+		cells = cells[:h]
+	} else {
+		cells = append(cells[:cap(cells)], make([][]TUIScreenCell, h-cap(cells))...)
+	}
+	for y, l := range cells {
+		if w <= cap(l) {
+			cells[y] = l[:w]
+			continue
+		}
+		n := make([]TUIScreenCell, w)
+		k := copy(n, l[:cap(l)])
+		for x := k; x < w; x++ {
+			n[x] = c
+		}
+		cells[y] = n
+	}
+	return cells
+}
+
+// NewTUIScreen returns new instance of TUIScreen of specific size
+func NewTUIScreen(w int, h int) *TUIScreen {
+	s := &TUIScreen{style: NewTUICellStyle()}
+	s.resize(w, h)
+	return s
+}
@@ -0,0 +1,214 @@
+package terminalui
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Colors used for cells with default colors in HTML and SVG
+const EXPORT_FG = 0xe5e5e5
+const EXPORT_BG = 0x000000
+
+// Size of a character cell in SVG (in pixels)
+const EXPORT_SVG_FONT_SIZE = 14
+const EXPORT_SVG_CELL_WIDTH = 8.4
+const EXPORT_SVG_CELL_HEIGHT = 17
+
+// xtermColors are RGB values of the first 16 colors of xterm palette
+var xtermColors = []int{
+	0x000000, 0xcd0000, 0x00cd00, 0xcdcd00, 0x0000ee, 0xcd00cd, 0x00cdcd, 0xe5e5e5,
+	0x7f7f7f, 0xff0000, 0x00ff00, 0xffff00, 0x5c5cff, 0xff00ff, 0x00ffff, 0xffffff,
+}
+
+// tuiScreenRun is a part of a line with characters of the same style. Width
+// is number of cells, which is more than number of characters when there
+// are wide ones.
+type tuiScreenRun struct {
+	x     int
+	width int
+	text  string
+	style TUICellStyle
+}
+
+// WriteText writes screen as plain text without colors
+func (s *TUIScreen) WriteText(w io.Writer) error {
+	_, err := io.WriteString(w, s.String()+"\n")
+	return err
+}
+
+// WriteHTML writes screen as standalone HTML document with colors and
+// attributes set on span elements
+func (s *TUIScreen) WriteHTML(w io.Writer) error {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>terminal-ui</title>\n")
+	fmt.Fprintf(&b, "<style>pre { display: inline-block; margin: 0; padding: 8px; font-family: monospace; line-height: 1.2; color: %s; background-color: %s; }</style>\n", cssColor(EXPORT_FG), cssColor(EXPORT_BG))
+	b.WriteString("</head>\n<body>\n<pre>")
+	for y, runs := range s.getRuns() {
+		if y > 0 {
+			b.WriteString("\n")
+		}
+		for _, r := range runs {
+			t := html.EscapeString(r.text)
+			css := getRunCSS(r.style)
+			if css == "" {
+				b.WriteString(t)
+				continue
+			}
+			b.WriteString("<span style=\"" + css + "\">" + t + "</span>")
+		}
+	}
+	b.WriteString("</pre>\n</body>\n</html>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteSVG writes screen as SVG image. Each part of a line with the same
+// style is a separate text element stretched to the width of its cells so
+// the columns stay aligned whatever font is used.
+func (s *TUIScreen) WriteSVG(w io.Writer) error {
+	lines := s.getRuns()
+	cw, ch := EXPORT_SVG_CELL_WIDTH, float64(EXPORT_SVG_CELL_HEIGHT)
+	width, height := cw*float64(s.GetWidth()), ch*float64(len(lines))
+	f := func(v float64) string {
+		return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n", f(width), f(height), f(width), f(height))
+	fmt.Fprintf(&b, "<rect width=\"100%%\" height=\"100%%\" fill=\"%s\"/>\n", cssColor(EXPORT_BG))
+	fmt.Fprintf(&b, "<g font-family=\"monospace\" font-size=\"%d\" xml:space=\"preserve\">\n", EXPORT_SVG_FONT_SIZE)
+	for y, runs := range lines {
+		for _, r := range runs {
+			n := r.width
+			fg, bg := getRunColors(r.style)
+			x := f(cw * float64(r.x))
+			if bg != EXPORT_BG {
+				fmt.Fprintf(&b, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" fill=\"%s\"/>\n", x, f(ch*float64(y)), f(cw*float64(n)), f(ch), cssColor(bg))
+			}
+			if strings.TrimSpace(r.text) == "" {
+				continue
+			}
+			a := " fill=\"" + cssColor(fg) + "\""
+			if r.style.Bold {
+				a += " font-weight=\"bold\""
+			}
+			if r.style.Italic {
+				a += " font-style=\"italic\""
+			}
+			if r.style.Dim {
+				a += " opacity=\"0.5\""
+			}
+			if d := getTextDecoration(r.style); d != "" {
+				a += " text-decoration=\"" + d + "\""
+			}
+			fmt.Fprintf(&b, "<text x=\"%s\" y=\"%s\" textLength=\"%s\" lengthAdjust=\"spacingAndGlyphs\"%s>%s</text>\n", x, f(ch*float64(y+1)-4), f(cw*float64(n)), a, html.EscapeString(r.text))
+		}
+	}
+	b.WriteString("</g>\n</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// getRuns returns lines split into parts of the same style
+func (s *TUIScreen) getRuns() [][]tuiScreenRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := make([][]tuiScreenRun, s.height)
+	for y, cells := range s.cells {
+		var r []rune
+		w := 0
+		for x, c := range cells {
+			if cr := getCellRune(cells, x); cr != 0 {
+				r = append(r, cr)
+			}
+			w++
+			if x == len(cells)-1 || cells[x+1].Style != c.Style {
+				lines[y] = append(lines[y], tuiScreenRun{x: x + 1 - w, width: w, text: string(r), style: c.Style})
+				r = r[:0]
+				w = 0
+			}
+		}
+	}
+	return lines
+}
+
+// getRunColors returns RGB values of foreground and background colors,
+// swapped when style is reversed
+func getRunColors(st TUICellStyle) (int, int) {
+	fg, bg := getRGB(st.FG, EXPORT_FG), getRGB(st.BG, EXPORT_BG)
+	if st.Reverse {
+		return bg, fg
+	}
+	return fg, bg
+}
+
+// getRunCSS returns CSS declarations of the style or empty string for the
+// default one
+func getRunCSS(st TUICellStyle) string {
+	var css []string
+	fg, bg := getRunColors(st)
+	if fg != EXPORT_FG {
+		css = append(css, "color: "+cssColor(fg))
+	}
+	if bg != EXPORT_BG {
+		css = append(css, "background-color: "+cssColor(bg))
+	}
+	if st.Bold {
+		css = append(css, "font-weight: bold")
+	}
+	if st.Italic {
+		css = append(css, "font-style: italic")
+	}
+	if st.Dim {
+		css = append(css, "opacity: 0.5")
+	}
+	if d := getTextDecoration(st); d != "" {
+		css = append(css, "text-decoration: "+d)
+	}
+	return strings.Join(css, "; ")
+}
+
+// getTextDecoration returns CSS text-decoration value of the style
+func getTextDecoration(st TUICellStyle) string {
+	var d []string
+	if st.Underline {
+		d = append(d, "underline")
+	}
+	if st.Strike {
+		d = append(d, "line-through")
+	}
+	return strings.Join(d, " ")
+}
+
+// getRGB returns 24-bit RGB value of the color, or def for COLOR_DEFAULT.
+// Numbers from 256 color palette are converted using xterm's palette.
+func getRGB(c int, def int) int {
+	switch {
+	case c == COLOR_DEFAULT:
+		return def
+	case c&COLOR_RGB != 0:
+		return c & 0xffffff
+	case c < 16:
+		return xtermColors[c]
+	case c < 232:
+		c -= 16
+		l := func(i int) int {
+			if i == 0 {
+				return 0
+			}
+			return 55 + i*40
+		}
+		return l(c/36)<<16 | l(c/6%6)<<8 | l(c%6)
+	}
+	g := 8 + (c-232)*10
+	return g<<16 | g<<8 | g
+}
+
+// cssColor returns RGB value in #rrggbb format
+func cssColor(c int) string {
+	return fmt.Sprintf("#%06x", c&0xffffff)
+}
@@ -0,0 +1,129 @@
+package terminalui
+
+import (
+	"strings"
+	"testing"
+)
+
+// screenLines returns all the lines of the screen
+func screenLines(s *TUIScreen) string {
+	return strings.Join(strings.Split(s.String(), "\n"), "|")
+}
+
+func TestScreen(t *testing.T) {
+	tests := []struct {
+		name   string
+		w, h   int
+		writes []string
+		lines  string
+		x, y   int
+	}{
+		{name: "text", w: 5, h: 2, writes: []string{"ab\r\ncd"}, lines: "ab|cd", x: 2, y: 1},
+		{name: "autowrap", w: 3, h: 2, writes: []string{"abcd"}, lines: "abc|d", x: 1, y: 1},
+		{name: "CUP", w: 5, h: 3, writes: []string{"\u001b[2;3Hx\u001b[Hy"}, lines: "y|  x|", x: 1, y: 0},
+		{name: "CUP clamped", w: 3, h: 2, writes: []string{"\u001b[9;9Hx"}, lines: "|  x", x: 2, y: 1},
+		{name: "CUP empty params", w: 3, h: 2, writes: []string{"ab\u001b[;Hx"}, lines: "xb|", x: 1, y: 0},
+		{name: "ED below", w: 3, h: 3, writes: []string{"abc\r\ndef\r\nghi\u001b[2;2H\u001b[J"}, lines: "abc|d|", x: 1, y: 1},
+		{name: "ED above", w: 3, h: 3, writes: []string{"abc\r\ndef\r\nghi\u001b[2;2H\u001b[1J"}, lines: "|  f|ghi", x: 1, y: 1},
+		{name: "ED all", w: 3, h: 2, writes: []string{"abc\r\ndef\u001b[2J"}, lines: "|", x: 2, y: 1},
+		{name: "EL right", w: 4, h: 1, writes: []string{"abcd\u001b[2G\u001b[K"}, lines: "a", x: 1, y: 0},
+		{name: "EL left", w: 4, h: 1, writes: []string{"abcd\u001b[2G\u001b[1K"}, lines: "  cd", x: 1, y: 0},
+		{name: "EL line", w: 4, h: 1, writes: []string{"abcd\u001b[2G\u001b[2K"}, lines: "", x: 1, y: 0},
+		{name: "DECSTBM LF", w: 3, h: 4, writes: []string{"a\r\nb\r\nc\r\nd\u001b[2;3r\u001b[3;1H\nx"}, lines: "a|c|x|d", x: 1, y: 2},
+		{name: "DECSTBM RI", w: 3, h: 4, writes: []string{"a\r\nb\r\nc\r\nd\u001b[2;3r\u001b[2;1H\u001bMx"}, lines: "a|x|b|d", x: 1, y: 1},
+		{name: "DECSTBM invalid", w: 3, h: 3, writes: []string{"a\u001b[3;2r\u001b[3;1H\nb"}, lines: "||b", x: 1, y: 2},
+		{name: "ICH", w: 5, h: 1, writes: []string{"abcde\u001b[2G\u001b[2@"}, lines: "a  bc", x: 1, y: 0},
+		{name: "DCH", w: 5, h: 1, writes: []string{"abcde\u001b[2G\u001b[2P"}, lines: "ade", x: 1, y: 0},
+		{name: "DCH past end", w: 5, h: 1, writes: []string{"abcde\u001b[4G\u001b[9P"}, lines: "abc", x: 3, y: 0},
+		{name: "alternate screen", w: 3, h: 2, writes: []string{"ab\u001b[?1049hx", "\u001b[?1049l"}, lines: "ab|", x: 2, y: 0},
+		{name: "alternate screen content", w: 3, h: 2, writes: []string{"ab\u001b[?1049h\u001b[Hx"}, lines: "x|", x: 1, y: 0},
+		{name: "split UTF-8", w: 3, h: 1, writes: []string{"\xc5", "\xbc\xe2\x94", "\x80"}, lines: "ż─", x: 2, y: 0},
+		{name: "split escape", w: 5, h: 2, writes: []string{"\u001b", "[2", ";", "3H", "x"}, lines: "|  x", x: 3, y: 1},
+		{name: "split OSC", w: 5, h: 1, writes: []string{"\u001b]0;ti", "tle\u001b", "\\x"}, lines: "x", x: 1, y: 0},
+		{name: "wide", w: 4, h: 1, writes: []string{"a世b"}, lines: "a世b", x: 3, y: 0},
+		{name: "wide wrapped", w: 3, h: 2, writes: []string{"ab世"}, lines: "ab|世", x: 2, y: 1},
+		{name: "wide overwritten", w: 4, h: 1, writes: []string{"世界\u001b[2Gx"}, lines: " x界", x: 2, y: 0},
+		{name: "REP", w: 5, h: 1, writes: []string{"ab\u001b[2b"}, lines: "abbb", x: 4, y: 0},
+		{name: "REP huge count", w: 3, h: 2, writes: []string{"a\u001b[999999999b"}, lines: "aaa|a", x: 1, y: 1},
+		{name: "combining skipped", w: 3, h: 1, writes: []string{"éx"}, lines: "ex", x: 2, y: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewTUIScreen(tt.w, tt.h)
+			for _, w := range tt.writes {
+				s.Write([]byte(w))
+			}
+			if got := screenLines(s); got != tt.lines {
+				t.Errorf("got lines %q, want %q", got, tt.lines)
+			}
+			if x, y := s.GetCursor(); x != tt.x || y != tt.y {
+				t.Errorf("got cursor %d,%d, want %d,%d", x, y, tt.x, tt.y)
+			}
+		})
+	}
+}
+
+func TestScreenReply(t *testing.T) {
+	tests := []struct {
+		name  string
+		write string
+		reply string
+	}{
+		{name: "status", write: "\u001b[5n", reply: "\u001b[0n"},
+		{name: "cursor position", write: "\u001b[3;4H\u001b[6n", reply: "\u001b[3;4R"},
+		{name: "split", write: "\u001b[2;2H\u001b[", reply: ""},
+		{name: "device attributes", write: "\u001b[c", reply: "\u001b[?1;2c"},
+		{name: "secondary device attributes", write: "\u001b[>c", reply: "\u001b[>0;0;0c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			s := NewTUIScreen(10, 5)
+			s.SetOnReply(func(b []byte) {
+				got += string(b)
+			})
+			s.Write([]byte(tt.write))
+			if got != tt.reply {
+				t.Errorf("got reply %q, want %q", got, tt.reply)
+			}
+		})
+	}
+}
+
+func TestScreenResize(t *testing.T) {
+	s := NewTUIScreen(4, 3)
+	s.Write([]byte("abcd\r\nefgh\r\nijkl\u001b[1;2r"))
+	s.Resize(4, 3)
+	s.Write([]byte("\u001b[2;1H\nx"))
+	if got := screenLines(s); got != "efgh|x|ijkl" {
+		t.Errorf("scroll region was reset by resize to the same size: %q", got)
+	}
+
+	s.Resize(2, 1)
+	if got := screenLines(s); got != "ef" {
+		t.Errorf("got %q after shrinking", got)
+	}
+	s.Resize(5, 3)
+	if got := screenLines(s); got != "efgh|x|ijkl" {
+		t.Errorf("content was not kept after growing back: %q", got)
+	}
+}
+
+func TestScreenEmpty(t *testing.T) {
+	s := NewTUIScreen(0, 0)
+	s.Write([]byte("ab\r\n\u001b[S\u001b[T\u001bM\u001b[L\u001b[M\u001b[@\u001b[P\u001b[J"))
+	if s.String() != "" {
+		t.Errorf("got %q", s.String())
+	}
+}
+
+func TestScreenStyledLineWide(t *testing.T) {
+	s := NewTUIScreen(4, 1)
+	s.Write([]byte("a世b"))
+	if got := s.getStyledLine(0, 4, false); got != "a世b" {
+		t.Errorf("got %q", got)
+	}
+	if got := s.getStyledLine(0, 2, false); got != "a " {
+		t.Errorf("wide character was not clipped: %q", got)
+	}
+}
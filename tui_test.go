@@ -0,0 +1,55 @@
+package terminalui
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPostWithoutRun(t *testing.T) {
+	tui := NewTUI()
+	called := false
+	tui.Post(func() {
+		called = true
+	})
+	if !called {
+		t.Error("function was not called straight away")
+	}
+}
+
+func TestPostFromGoroutines(t *testing.T) {
+	tui := NewTUI()
+	tui.SetSize(40, 10)
+	o := NewTUIOverlay("o", tui, 10, 4)
+	runTUI(t, tui)
+
+	// n is not guarded, so the race detector fails the test when posted
+	// functions are not called on one goroutine
+	n := 0
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				tui.Post(func() {
+					n++
+				})
+				o.Open()
+				o.SetZ(j)
+				o.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	var got, overlays int
+	tui.PostAndWait(func() {
+		got = n
+		overlays = len(tui.GetOverlays())
+	})
+	if got != 1000 {
+		t.Errorf("%d functions were called, want 1000", got)
+	}
+	if overlays != 0 {
+		t.Errorf("%d overlays are open", overlays)
+	}
+}
@@ -0,0 +1,227 @@
+package terminalui
+
+import (
+	"time"
+)
+
+// TUITimer calls a function attached to a pane after some time, once or
+// repeatedly. Function is called on the UI goroutine. Timer is stopped
+// automatically when its pane is removed from the interface, is not shown
+// anymore (eg. it is in a tab that is not active) or one of its funcs
+// panicked.
+type TUITimer struct {
+	pane     *TUIPane
+	interval int
+	repeat   bool
+	f        func(*TUIPane)
+	timer    *time.Timer
+	stopped  bool
+}
+
+// GetPane returns pane that the timer is attached to
+func (tm *TUITimer) GetPane() *TUIPane {
+	return tm.pane
+}
+
+// IsStopped returns true when timer will not call its function anymore
+func (tm *TUITimer) IsStopped() bool {
+	t := tm.pane.tui
+	if t == nil {
+		return tm.stopped
+	}
+	t.eventsMu.Lock()
+	defer t.eventsMu.Unlock()
+	return tm.stopped
+}
+
+// Stop cancels the timer
+func (tm *TUITimer) Stop() {
+	t := tm.pane.tui
+	if t == nil {
+		return
+	}
+	t.eventsMu.Lock()
+	tm.stop()
+	t.eventsMu.Unlock()
+}
+
+// stop cancels the timer and forgets it. It has to be called with eventsMu
+// locked.
+func (tm *TUITimer) stop() {
+	tm.stopped = true
+	if tm.timer != nil {
+		tm.timer.Stop()
+	}
+	delete(tm.pane.tui.timers, tm)
+}
+
+// start schedules the timer to fire after its interval unless it is
+// already started
+func (tm *TUITimer) start() {
+	t := tm.pane.tui
+	t.eventsMu.Lock()
+	defer t.eventsMu.Unlock()
+	if tm.stopped || tm.timer != nil {
+		return
+	}
+	tm.schedule()
+}
+
+// schedule makes the timer fire after its interval. It has to be called with
+// eventsMu locked.
+func (tm *TUITimer) schedule() {
+	tm.timer = time.AfterFunc(time.Millisecond*time.Duration(tm.interval), tm.fire)
+}
+
+// fire posts calling timer function to the UI goroutine, unless the pane is
+// not shown anymore in which case the timer is stopped
+func (tm *TUITimer) fire() {
+	t := tm.pane.tui
+	t.Post(func() {
+		t.eventsMu.Lock()
+		if tm.stopped {
+			t.eventsMu.Unlock()
+			return
+		}
+		if !t.isPaneShown(tm.pane) || tm.pane.panic != nil {
+			tm.stop()
+			t.eventsMu.Unlock()
+			return
+		}
+		if tm.repeat {
+			tm.schedule()
+		} else {
+			tm.stop()
+		}
+		t.eventsMu.Unlock()
+		tm.pane.call(func(p *TUIPane) int {
+			tm.f(p)
+			return RESULT_OK
+		})
+	})
+}
+
+// AfterFunc calls function once after specified number of milliseconds.
+// When TUI is not running yet, time is counted from the moment Run is called.
+// When pane was created without TUI, returned timer is already stopped.
+func (p *TUIPane) AfterFunc(ms int, f func(*TUIPane)) *TUITimer {
+	return p.addTimer(ms, false, f)
+}
+
+// Every calls function every specified number of milliseconds until the
+// timer is stopped. When TUI is not running yet, time is counted from the
+// moment Run is called. When pane was created without TUI, returned timer is
+// already stopped.
+func (p *TUIPane) Every(ms int, f func(*TUIPane)) *TUITimer {
+	return p.addTimer(ms, true, f)
+}
+
+// addTimer creates a timer and starts it when TUI is running
+func (p *TUIPane) addTimer(ms int, repeat bool, f func(*TUIPane)) *TUITimer {
+	tm := &TUITimer{
+		pane:     p,
+		interval: ms,
+		repeat:   repeat,
+		f:        f,
+	}
+	t := p.tui
+	if t == nil {
+		tm.stopped = true
+		return tm
+	}
+	t.eventsMu.Lock()
+	if t.timers == nil {
+		t.timers = map[*TUITimer]bool{}
+	}
+	t.timers[tm] = true
+	running := t.running
+	t.eventsMu.Unlock()
+	if running {
+		tm.start()
+	}
+	return tm
+}
+
+// startTimers starts timers that were created before Run
+func (t *TUI) startTimers() {
+	t.eventsMu.Lock()
+	tms := make([]*TUITimer, 0, len(t.timers))
+	for tm := range t.timers {
+		tms = append(tms, tm)
+	}
+	t.eventsMu.Unlock()
+	for _, tm := range tms {
+		tm.start()
+	}
+}
+
+// stopTimers stops timers of the pane and all the panes inside it, or all
+// the timers when pane is nil
+func (t *TUI) stopTimers(p *TUIPane) {
+	t.eventsMu.Lock()
+	defer t.eventsMu.Unlock()
+	for tm := range t.timers {
+		if p == nil || tm.pane == p || p.isAncestorOf(tm.pane) {
+			tm.stop()
+		}
+	}
+}
+
+// GetIterateInterval returns number of milliseconds between calls to
+// onIterate. Value of 0 means it is called with every main loop iteration.
+func (p *TUIPane) GetIterateInterval() int {
+	return p.iterateInterval
+}
+
+// SetIterateInterval sets number of milliseconds between calls to onIterate,
+// so panes can be updated more or less often than TUI loop sleep. Value of 0
+// means onIterate is called with every main loop iteration.
+func (p *TUIPane) SetIterateInterval(ms int) {
+	p.iterateInterval = ms
+	if p.tui != nil {
+		p.tui.wakeUp()
+	}
+}
+
+// getIterateInterval returns time between calls to onIterate
+func (p *TUIPane) getIterateInterval() time.Duration {
+	ms := p.iterateInterval
+	if ms <= 0 && p.tui != nil {
+		ms = p.tui.loopSleep
+	}
+	return time.Millisecond * time.Duration(ms)
+}
+
+// getNextIterate returns time when onIterate of any of the shown panes should
+// be called next, or n when it is earlier
+func (p *TUIPane) getNextIterate(n time.Time) time.Time {
+	if p.width <= 0 || p.height <= 0 || p.tooSmall {
+		return n
+	}
+	if p.split == SPLIT_TABS {
+		if len(p.tabs) > 0 {
+			return p.tabs[p.activeTab].getNextIterate(n)
+		}
+		return n
+	}
+	if p.split != SPLIT_NONE {
+		return p.panes[1].getNextIterate(p.panes[0].getNextIterate(n))
+	}
+	if p.onIterate == nil || p.covered {
+		return n
+	}
+	if d := p.lastIterate.Add(p.getIterateInterval()); d.Before(n) {
+		return d
+	}
+	return n
+}
+
+// getIterateDelay returns time main loop should wait until the next
+// iteration. It is never longer than loop sleep.
+func (t *TUI) getIterateDelay(now time.Time) time.Duration {
+	n := t.getVisiblePane().getNextIterate(now.Add(time.Millisecond * time.Duration(t.loopSleep)))
+	for _, o := range t.overlays {
+		n = o.pane.getNextIterate(n)
+	}
+	return n.Sub(now)
+}
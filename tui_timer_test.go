@@ -0,0 +1,170 @@
+package terminalui
+
+import (
+	"io"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// runTUI starts main loop of the TUI in the background and stops it when the
+// test finishes
+func runTUI(t *testing.T, tui *TUI) {
+	r, w := io.Pipe()
+	tui.SetInput(r)
+	tui.SetOutput(io.Discard)
+	done := make(chan int)
+	go func() {
+		done <- tui.Run(nil, nil)
+	}()
+	for !tui.isRunning() {
+		runtime.Gosched()
+	}
+	t.Cleanup(func() {
+		tui.Stop(0)
+		<-done
+		w.Close()
+	})
+}
+
+func TestTimerWithoutTUI(t *testing.T) {
+	p := NewTUIPane("p", nil)
+	tm := p.Every(10, func(p *TUIPane) {})
+	if !tm.IsStopped() {
+		t.Errorf("timer of pane without TUI is not stopped")
+	}
+	tm.Stop()
+	if !p.AfterFunc(10, func(p *TUIPane) {}).IsStopped() {
+		t.Errorf("timer of pane without TUI is not stopped")
+	}
+}
+
+func TestIterateDelay(t *testing.T) {
+	now := time.Now()
+	for _, tt := range []struct {
+		name     string
+		interval int
+		last     time.Duration
+		hidden   bool
+		want     time.Duration
+	}{
+		{name: "loop sleep", interval: 0, last: 300 * time.Millisecond, want: 700 * time.Millisecond},
+		{name: "shorter interval", interval: 100, last: 30 * time.Millisecond, want: 70 * time.Millisecond},
+		{name: "overdue", interval: 100, last: 200 * time.Millisecond, want: -100 * time.Millisecond},
+		{name: "longer interval", interval: 5000, last: 0, want: time.Second},
+		{name: "hidden tab", interval: 100, last: 30 * time.Millisecond, hidden: true, want: time.Second},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tui := newTreeTUI(40, 10)
+			tui.SetLoopSleep(1000)
+			p := tui.GetPane()
+			if tt.hidden {
+				p.AddTab("a")
+				p = p.AddTab("b")
+				tui.layout()
+			}
+			p.SetOnIterate(func(p *TUIPane) int {
+				return RESULT_OK
+			})
+			p.SetIterateInterval(tt.interval)
+			p.lastIterate = now.Add(-tt.last)
+			if got := tui.getIterateDelay(now); got != tt.want {
+				t.Errorf("got delay %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIterateInterval(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	n := 0
+	p := tui.GetPane()
+	p.SetOnIterate(func(p *TUIPane) int {
+		n++
+		return RESULT_OK
+	})
+	p.SetIterateInterval(100)
+	now := time.Now()
+	for _, tt := range []struct {
+		after time.Duration
+		want  int
+	}{
+		{after: 0, want: 1},
+		{after: 50 * time.Millisecond, want: 1},
+		{after: 99 * time.Millisecond, want: 1},
+		{after: 100 * time.Millisecond, want: 2},
+		{after: 150 * time.Millisecond, want: 2},
+		{after: 250 * time.Millisecond, want: 3},
+	} {
+		p.iterate(now.Add(tt.after))
+		if n != tt.want {
+			t.Errorf("after %v: onIterate called %d times, want %d", tt.after, n, tt.want)
+		}
+	}
+}
+
+func TestTimerStopsForHiddenPane(t *testing.T) {
+	tui := NewTUI()
+	tui.SetSize(40, 10)
+	a := tui.GetPane().AddTab("a")
+	b := tui.GetPane().AddTab("b")
+	shown := make(chan bool)
+	a.AfterFunc(10, func(p *TUIPane) {
+		close(shown)
+	})
+	called := false
+	tm := b.Every(10, func(p *TUIPane) {
+		called = true
+	})
+	runTUI(t, tui)
+
+	select {
+	case <-shown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timer of active tab was not called")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !tm.IsStopped() {
+		if time.Now().After(deadline) {
+			t.Fatal("timer of inactive tab was not stopped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	tui.PostAndWait(func() {
+		if called {
+			t.Error("timer of inactive tab was called")
+		}
+	})
+}
+
+func TestShorterIntervalWakesUp(t *testing.T) {
+	tui := NewTUI()
+	tui.SetSize(40, 10)
+	tui.SetLoopSleep(60000)
+	iterated := make(chan bool, 10)
+	p := tui.GetPane()
+	p.SetOnIterate(func(p *TUIPane) int {
+		select {
+		case iterated <- true:
+		default:
+		}
+		return RESULT_OK
+	})
+	runTUI(t, tui)
+
+	// first iteration happens straight away and the next one is a minute
+	// later
+	select {
+	case <-iterated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("onIterate was not called")
+	}
+	tui.Post(func() {
+		p.SetIterateInterval(10)
+	})
+	select {
+	case <-iterated:
+	case <-time.After(5 * time.Second):
+		t.Fatal("main loop did not wake up when iterate interval got shorter")
+	}
+}
@@ -0,0 +1,99 @@
+package terminalui
+
+import (
+	"strings"
+	"sync"
+)
+
+// TUIWidgetLog is a widget that shows lines of text with the newest ones at
+// the bottom. Lines can be added from any goroutine and the pane is redrawn
+// straight away. Only limited number of the last lines is kept.
+type TUIWidgetLog struct {
+	lines []string
+	max   int
+	pane  *TUIPane
+	mu    sync.Mutex
+}
+
+// GetLines returns lines that are kept
+func (w *TUIWidgetLog) GetLines() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string{}, w.lines...)
+}
+
+// Add appends text to the log. Text with new line characters is split into
+// many lines. Text can have ANSI escape sequences setting colors (see
+// ParseANSI).
+func (w *TUIWidgetLog) Add(s string) {
+	w.mu.Lock()
+	w.lines = append(w.lines, splitANSILines(strings.TrimRight(s, "\n"))...)
+	if len(w.lines) > w.max {
+		w.lines = w.lines[len(w.lines)-w.max:]
+	}
+	p := w.pane
+	w.mu.Unlock()
+	if p != nil {
+		p.Invalidate()
+	}
+}
+
+// Clear removes all the lines
+func (w *TUIWidgetLog) Clear() {
+	w.mu.Lock()
+	w.lines = nil
+	p := w.pane
+	w.mu.Unlock()
+	if p != nil {
+		p.Invalidate()
+	}
+}
+
+// InitPane sets pane minimal width and height that's necessary for the pane
+// to work, and attaches the widget to the pane so it is redrawn when lines
+// are added and it can be found by GetLog.
+func (w *TUIWidgetLog) InitPane(p *TUIPane) {
+	p.SetMinWidth(5)
+	p.SetMinHeight(1)
+	w.mu.Lock()
+	w.pane = p
+	w.mu.Unlock()
+	p.log = w
+}
+
+// Run prints out the last lines that fit in the pane
+func (w *TUIWidgetLog) Run(p *TUIPane) int {
+	cw := p.GetContentWidth()
+	ch := p.GetContentHeight()
+	if cw <= 0 || ch <= 0 {
+		return RESULT_OK
+	}
+	w.mu.Lock()
+	lines := w.lines
+	if len(lines) > ch {
+		lines = lines[len(lines)-ch:]
+	}
+	lines = append([]string{}, lines...)
+	w.mu.Unlock()
+	for i := 0; i < ch; i++ {
+		l := ""
+		if i < len(lines) {
+			l = lines[i]
+		}
+		p.Write(0, i, FitANSI(l, cw), false)
+	}
+	return RESULT_OK
+}
+
+// GetLog returns log widget attached to the pane with TUIWidgetLog.InitPane
+// or nil
+func (p *TUIPane) GetLog() *TUIWidgetLog {
+	return p.log
+}
+
+// NewTUIWidgetLog returns new instance of TUIWidgetLog that keeps max number
+// of the last lines
+func NewTUIWidgetLog(max int) *TUIWidgetLog {
+	w := &TUIWidgetLog{max: max}
+	return w
+}
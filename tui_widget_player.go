@@ -0,0 +1,202 @@
+package terminalui
+
+import (
+	"time"
+)
+
+// TUIWidgetPlayer is a widget that plays asciicast inside a pane. Output
+// events are interpreted by TUIScreen of the recorded terminal size, which
+// is drawn in the pane (and clipped when pane is smaller).
+type TUIWidgetPlayer struct {
+	cast    *TUIAsciicast
+	screen  *TUIScreen
+	next    int
+	offset  float64
+	started time.Time
+	playing bool
+	speed   float64
+	loop    bool
+	timer   *TUITimer
+	pane    *TUIPane
+}
+
+// GetScreen returns screen that the asciicast is played on
+func (w *TUIWidgetPlayer) GetScreen() *TUIScreen {
+	return w.screen
+}
+
+// GetPosition returns current position in seconds
+func (w *TUIWidgetPlayer) GetPosition() float64 {
+	if !w.playing {
+		return w.offset
+	}
+	return w.offset + time.Since(w.started).Seconds()*w.speed
+}
+
+// IsPlaying returns true when asciicast is being played
+func (w *TUIWidgetPlayer) IsPlaying() bool {
+	return w.playing
+}
+
+// GetSpeed returns playback speed
+func (w *TUIWidgetPlayer) GetSpeed() float64 {
+	return w.speed
+}
+
+// SetSpeed sets playback speed, eg. 2 plays it twice as fast
+func (w *TUIWidgetPlayer) SetSpeed(s float64) {
+	if s <= 0 {
+		return
+	}
+	w.offset = w.GetPosition()
+	w.started = time.Now()
+	w.speed = s
+	w.schedule()
+}
+
+// SetLoop sets whether asciicast starts again when it ends
+func (w *TUIWidgetPlayer) SetLoop(b bool) {
+	w.loop = b
+}
+
+// Play starts or resumes playing
+func (w *TUIWidgetPlayer) Play() {
+	if w.playing {
+		return
+	}
+	if w.next >= len(w.cast.Events) {
+		w.rewind()
+	}
+	w.playing = true
+	w.started = time.Now()
+	w.schedule()
+}
+
+// Pause stops playing at current position
+func (w *TUIWidgetPlayer) Pause() {
+	if !w.playing {
+		return
+	}
+	w.offset = w.GetPosition()
+	w.playing = false
+	w.stopTimer()
+}
+
+// Rewind moves to the beginning of the asciicast
+func (w *TUIWidgetPlayer) Rewind() {
+	w.rewind()
+	if w.playing {
+		w.started = time.Now()
+		w.schedule()
+	}
+	w.invalidate()
+}
+
+// KeyPress handles key pressed while the player is active: space pauses or
+// resumes playing and home key rewinds it. It returns false when the key was
+// not used by the player.
+func (w *TUIWidgetPlayer) KeyPress(p *TUIPane, b []byte) bool {
+	switch string(b) {
+	case KEY_SPACE:
+		if w.playing {
+			w.Pause()
+		} else {
+			w.Play()
+		}
+	case KEY_HOME:
+		w.Rewind()
+	default:
+		return false
+	}
+	return true
+}
+
+// InitPane sets pane minimal width and height that's necessary for the pane
+// to work, and starts playing
+func (w *TUIWidgetPlayer) InitPane(p *TUIPane) {
+	p.SetMinWidth(5)
+	p.SetMinHeight(1)
+	w.pane = p
+	w.playing = false
+	w.Play()
+}
+
+// Run draws the screen. When pane was hidden (eg. in a tab that is not
+// active) playing continues from the current position.
+func (w *TUIWidgetPlayer) Run(p *TUIPane) int {
+	if w.playing && (w.timer == nil || w.timer.IsStopped()) {
+		w.schedule()
+	}
+	w.screen.Draw(p)
+	return RESULT_OK
+}
+
+// rewind resets the screen and position
+func (w *TUIWidgetPlayer) rewind() {
+	w.screen.Resize(w.cast.Width, w.cast.Height)
+	w.screen.Reset()
+	w.next = 0
+	w.offset = 0
+}
+
+// step applies events that are due, redraws the pane and schedules the next
+// step
+func (w *TUIWidgetPlayer) step(p *TUIPane) {
+	w.timer = nil
+	pos := w.GetPosition()
+	for ; w.next < len(w.cast.Events) && w.cast.Events[w.next].Time <= pos; w.next++ {
+		e := w.cast.Events[w.next]
+		switch e.Type {
+		case ASCIICAST_OUTPUT:
+			w.screen.Write([]byte(e.Data))
+		case ASCIICAST_RESIZE:
+			if cw, ch, ok := parseAsciicastSize(e.Data); ok {
+				w.screen.Resize(cw, ch)
+			}
+		}
+	}
+	p.Invalidate()
+	if w.next >= len(w.cast.Events) {
+		if !w.loop {
+			w.offset = w.cast.GetDuration()
+			w.playing = false
+			return
+		}
+		w.rewind()
+		w.started = time.Now()
+	}
+	w.schedule()
+}
+
+// schedule starts timer that fires when the next event is due
+func (w *TUIWidgetPlayer) schedule() {
+	w.stopTimer()
+	if !w.playing || w.pane == nil || w.next >= len(w.cast.Events) {
+		return
+	}
+	d := (w.cast.Events[w.next].Time - w.GetPosition()) / w.speed
+	w.timer = w.pane.AfterFunc(max(int(d*1000), 0), w.step)
+}
+
+// stopTimer stops timer of the next step
+func (w *TUIWidgetPlayer) stopTimer() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+}
+
+// invalidate redraws the pane if widget is attached to one
+func (w *TUIWidgetPlayer) invalidate() {
+	if w.pane != nil {
+		w.pane.Invalidate()
+	}
+}
+
+// NewTUIWidgetPlayer returns new instance of TUIWidgetPlayer that plays the
+// asciicast
+func NewTUIWidgetPlayer(c *TUIAsciicast) *TUIWidgetPlayer {
+	w := &TUIWidgetPlayer{cast: c, speed: 1}
+	w.screen = NewTUIScreen(c.Width, c.Height)
+	return w
+}
@@ -0,0 +1,316 @@
+package terminalui
+
+import (
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/creack/pty"
+)
+
+// cursor keys sent in application cursor keys mode
+var appCursorKeys = map[string]string{
+	KEY_UP: "\u001bOA", KEY_DOWN: "\u001bOB", KEY_RIGHT: "\u001bOC", KEY_LEFT: "\u001bOD",
+	KEY_HOME: "\u001bOH", KEY_END: "\u001bOF",
+}
+
+// TUIWidgetTerminal is a widget that runs a command inside a pseudo-terminal
+// and shows its output in the pane, like a terminal emulator. Command is
+// started when the pane is drawn for the first time, with the size of pane
+// content, and the pseudo-terminal is resized with the pane. Keys are
+// passed to the command when the pane has focus (see TUI.SetFocus), except
+// the ones attached with TUI.BindKey. While the command runs, Ctrl+C, Ctrl+Z
+// and Ctrl+\ do not send signals to the interface but are passed to the
+// command as well.
+type TUIWidgetTerminal struct {
+	cmd      *exec.Cmd
+	pty      *os.File
+	screen   *TUIScreen
+	pane     *TUIPane
+	cols     int
+	rows     int
+	started  bool
+	exited   bool
+	err      error
+	startErr error
+	onExit   func(*TUIPane, error)
+	mu       sync.Mutex
+}
+
+// GetCmd returns command that is run
+func (w *TUIWidgetTerminal) GetCmd() *exec.Cmd {
+	return w.cmd
+}
+
+// GetScreen returns screen that output of the command is written to
+func (w *TUIWidgetTerminal) GetScreen() *TUIScreen {
+	return w.screen
+}
+
+// IsRunning returns true when the command has been started and has not
+// exited yet
+func (w *TUIWidgetTerminal) IsRunning() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.started && !w.exited
+}
+
+// GetError returns error of starting or running the command
+func (w *TUIWidgetTerminal) GetError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// SetOnExit attaches function that is called on the UI goroutine when the
+// command exits. It gets the pane and error (nil when command exited with 0).
+func (w *TUIWidgetTerminal) SetOnExit(f func(*TUIPane, error)) {
+	w.onExit = f
+}
+
+// Write sends bytes to the command's input as if they were typed
+func (w *TUIWidgetTerminal) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	f := w.pty
+	w.mu.Unlock()
+	if f == nil {
+		return 0, os.ErrClosed
+	}
+	return f.Write(b)
+}
+
+// Close kills the command and closes the pseudo-terminal
+func (w *TUIWidgetTerminal) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pty == nil {
+		return nil
+	}
+	if !w.exited && w.cmd.Process != nil {
+		w.cmd.Process.Kill()
+	}
+	err := w.pty.Close()
+	w.pty = nil
+	return err
+}
+
+// KeyPress passes key to the command. Enter is sent as carriage return and
+// arrow keys are translated when command uses application cursor keys. It
+// returns false when command is not running.
+func (w *TUIWidgetTerminal) KeyPress(p *TUIPane, b []byte) bool {
+	if !w.IsRunning() {
+		return false
+	}
+	k := string(b)
+	if k == KEY_ENTER {
+		k = "\r"
+	} else if a, ok := appCursorKeys[k]; ok && w.screen.IsAppCursor() {
+		k = a
+	}
+	w.Write([]byte(k))
+	return true
+}
+
+// InitPane sets pane minimal width and height that's necessary for the pane
+// to work, and attaches the widget to the pane so it gets keys when pane
+// has focus
+func (w *TUIWidgetTerminal) InitPane(p *TUIPane) {
+	p.SetMinWidth(2)
+	p.SetMinHeight(1)
+	w.pane = p
+	p.terminal = w
+}
+
+// Run starts the command when it is not started yet and draws its screen,
+// with the cursor when pane has focus. When the command could not be
+// started, the error is shown instead.
+func (w *TUIWidgetTerminal) Run(p *TUIPane) int {
+	cw, ch := p.GetContentWidth(), p.GetContentHeight()
+	if cw <= 0 || ch <= 0 {
+		return RESULT_OK
+	}
+	w.mu.Lock()
+	started := w.started
+	w.mu.Unlock()
+	if !started {
+		w.start(cw, ch)
+		if p.tui != nil {
+			p.tui.updateSignals()
+		}
+	}
+	if err := w.getStartError(); err != nil {
+		for i := 0; i < ch; i++ {
+			l := ""
+			if i == 0 {
+				l = err.Error()
+			}
+			p.Write(0, i, fitString(l, cw), false)
+		}
+		return RESULT_OK
+	}
+	w.screen.draw(p, p.tui != nil && p.tui.focus == p && w.IsRunning())
+	return RESULT_OK
+}
+
+// start starts the command in a pseudo-terminal of specific size
+func (w *TUIWidgetTerminal) start(cw int, ch int) {
+	w.screen.Resize(cw, ch)
+	f, err := pty.StartWithSize(w.cmd, &pty.Winsize{Cols: uint16(cw), Rows: uint16(ch)})
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.started = true
+	w.cols, w.rows = cw, ch
+	if err != nil {
+		w.exited = true
+		w.err = err
+		w.startErr = err
+		return
+	}
+	w.pty = f
+	go w.read(f)
+}
+
+// getStartError returns error of starting the command
+func (w *TUIWidgetTerminal) getStartError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.startErr
+}
+
+// read writes output of the command to the screen until the command exits
+func (w *TUIWidgetTerminal) read(f *os.File) {
+	b := make([]byte, 4096)
+	for {
+		n, err := f.Read(b)
+		if n > 0 {
+			w.screen.Write(b[:n])
+			if w.pane != nil {
+				w.pane.Invalidate()
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	err := w.cmd.Wait()
+	w.mu.Lock()
+	w.exited = true
+	w.err = err
+	w.mu.Unlock()
+	p := w.pane
+	if p == nil || p.tui == nil {
+		return
+	}
+	p.tui.Post(func() {
+		p.Invalidate()
+		if w.onExit != nil {
+			w.onExit(p, err)
+		}
+		p.tui.updateSignals()
+	})
+}
+
+// resize changes size of the screen and the pseudo-terminal to the size of
+// pane content when it has changed
+func (w *TUIWidgetTerminal) resize(p *TUIPane) {
+	cw, ch := p.GetContentWidth(), p.GetContentHeight()
+	if cw <= 0 || ch <= 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if cw == w.cols && ch == w.rows {
+		return
+	}
+	w.cols, w.rows = cw, ch
+	w.screen.Resize(cw, ch)
+	if w.pty != nil && !w.exited {
+		pty.Setsize(w.pty, &pty.Winsize{Cols: uint16(cw), Rows: uint16(ch)})
+	}
+}
+
+// GetTerminal returns terminal widget attached to the pane with
+// TUIWidgetTerminal.InitPane or nil
+func (p *TUIPane) GetTerminal() *TUIWidgetTerminal {
+	return p.terminal
+}
+
+// closeTerminals closes terminal widgets in the pane and all the panes inside
+// it
+func (p *TUIPane) closeTerminals() {
+	p.Walk(func(c *TUIPane, d int) bool {
+		if c.terminal != nil {
+			c.terminal.Close()
+		}
+		return true
+	})
+}
+
+// closeTerminals closes all the terminal widgets in panes and overlays
+func (t *TUI) closeTerminals() {
+	t.pane.closeTerminals()
+	for _, o := range t.overlays {
+		o.pane.closeTerminals()
+	}
+}
+
+// walkTerminals calls f for each terminal widget in panes and overlays
+func (t *TUI) walkTerminals(f func(*TUIPane, *TUIWidgetTerminal)) {
+	g := func(c *TUIPane, d int) bool {
+		if c.terminal != nil {
+			f(c, c.terminal)
+		}
+		return true
+	}
+	t.pane.Walk(g)
+	for _, o := range t.overlays {
+		o.pane.Walk(g)
+	}
+}
+
+// resizeTerminals resizes terminal widgets to the size of their panes. It
+// is called once panes have their final size so each command gets only one
+// SIGWINCH.
+func (t *TUI) resizeTerminals() {
+	t.walkTerminals(func(p *TUIPane, w *TUIWidgetTerminal) {
+		w.resize(p)
+	})
+}
+
+// updateSignals turns off generating signals with Ctrl+C, Ctrl+Z and Ctrl+\
+// on the tty while any terminal widget runs a command, so the keys can be
+// passed to it, and turns it back on afterwards
+func (t *TUI) updateSignals() {
+	if t.tty == nil || !t.isRunning() {
+		return
+	}
+	running := false
+	t.walkTerminals(func(p *TUIPane, w *TUIWidgetTerminal) {
+		running = running || w.IsRunning()
+	})
+	if running == t.noSignals {
+		return
+	}
+	arg := "isig"
+	if running {
+		arg = "-isig"
+	}
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = t.tty
+	if cmd.Run() == nil {
+		t.noSignals = running
+	}
+}
+
+// NewTUIWidgetTerminal returns new instance of TUIWidgetTerminal that runs
+// command with arguments. TERM environment variable is set to
+// xterm-256color.
+func NewTUIWidgetTerminal(name string, args ...string) *TUIWidgetTerminal {
+	w := &TUIWidgetTerminal{cmd: exec.Command(name, args...)}
+	w.cmd.Env = append(os.Environ(), "TERM=xterm-256color")
+	w.screen = NewTUIScreen(0, 0)
+	w.screen.SetOnReply(func(b []byte) {
+		w.Write(b)
+	})
+	return w
+}
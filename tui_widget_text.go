@@ -0,0 +1,75 @@
+package terminalui
+
+import (
+	"strings"
+	"sync"
+)
+
+// TUIWidgetText is a widget that shows text, eg. output of a command. Text
+// can have ANSI escape sequences setting colors, which are kept, while other
+// escape sequences are removed (see ParseANSI). Lines that do not fit in the
+// pane are cut.
+type TUIWidgetText struct {
+	text  string
+	lines []string
+	pane  *TUIPane
+	mu    sync.Mutex
+}
+
+// GetText returns text
+func (w *TUIWidgetText) GetText() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.text
+}
+
+// SetText sets text and redraws the pane. It can be called from any
+// goroutine.
+func (w *TUIWidgetText) SetText(s string) {
+	w.mu.Lock()
+	w.text = s
+	w.lines = splitANSILines(strings.TrimRight(s, "\n"))
+	p := w.pane
+	w.mu.Unlock()
+	if p != nil {
+		p.Invalidate()
+	}
+}
+
+// InitPane sets pane minimal width and height that's necessary for the pane
+// to work, and attaches the widget to the pane so it is redrawn when text
+// is set
+func (w *TUIWidgetText) InitPane(p *TUIPane) {
+	p.SetMinWidth(1)
+	p.SetMinHeight(1)
+	w.mu.Lock()
+	w.pane = p
+	w.mu.Unlock()
+}
+
+// Run prints out the lines that fit in the pane
+func (w *TUIWidgetText) Run(p *TUIPane) int {
+	cw := p.GetContentWidth()
+	ch := p.GetContentHeight()
+	if cw <= 0 || ch <= 0 {
+		return RESULT_OK
+	}
+	w.mu.Lock()
+	lines := w.lines
+	w.mu.Unlock()
+	for i := 0; i < ch; i++ {
+		l := ""
+		if i < len(lines) {
+			l = lines[i]
+		}
+		p.Write(0, i, FitANSI(l, cw), false)
+	}
+	return RESULT_OK
+}
+
+// NewTUIWidgetText returns new instance of TUIWidgetText with text
+func NewTUIWidgetText(s string) *TUIWidgetText {
+	w := &TUIWidgetText{}
+	w.SetText(s)
+	return w
+}
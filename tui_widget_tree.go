@@ -0,0 +1,424 @@
+package terminalui
+
+import (
+	"strings"
+	"unicode"
+)
+
+// TUIWidgetTreeNode is a single node of the tree. It has a label that is
+// printed out and any data attached to it. Children can be added upfront or
+// loaded when node gets expanded for the first time (see SetOnLoad on
+// TUIWidgetTree).
+type TUIWidgetTreeNode struct {
+	label    string
+	data     interface{}
+	parent   *TUIWidgetTreeNode
+	children []*TUIWidgetTreeNode
+	expanded bool
+	loaded   bool
+	leaf     bool
+}
+
+// GetLabel returns label
+func (n *TUIWidgetTreeNode) GetLabel() string {
+	return n.label
+}
+
+// GetData returns data attached to the node
+func (n *TUIWidgetTreeNode) GetData() interface{} {
+	return n.data
+}
+
+// GetParent returns parent node
+func (n *TUIWidgetTreeNode) GetParent() *TUIWidgetTreeNode {
+	return n.parent
+}
+
+// GetChildren returns child nodes
+func (n *TUIWidgetTreeNode) GetChildren() []*TUIWidgetTreeNode {
+	return n.children
+}
+
+// IsExpanded returns true when node children are shown
+func (n *TUIWidgetTreeNode) IsExpanded() bool {
+	return n.expanded
+}
+
+// SetLabel sets label
+func (n *TUIWidgetTreeNode) SetLabel(l string) {
+	n.label = l
+}
+
+// SetData sets data attached to the node
+func (n *TUIWidgetTreeNode) SetData(d interface{}) {
+	n.data = d
+}
+
+// SetLeaf marks node as the one that never has any children so the load
+// func is not called for it
+func (n *TUIWidgetTreeNode) SetLeaf(b bool) {
+	n.leaf = b
+}
+
+// AddChild appends a child node and returns it
+func (n *TUIWidgetTreeNode) AddChild(c *TUIWidgetTreeNode) *TUIWidgetTreeNode {
+	c.parent = n
+	n.children = append(n.children, c)
+	n.loaded = true
+	return c
+}
+
+// ClearChildren removes all child nodes so they will be loaded again on the
+// next expand
+func (n *TUIWidgetTreeNode) ClearChildren() {
+	n.children = nil
+	n.loaded = false
+}
+
+// isLast returns true when node is the last child of its parent
+func (n *TUIWidgetTreeNode) isLast() bool {
+	if n.parent == nil {
+		return true
+	}
+	return n.parent.children[len(n.parent.children)-1] == n
+}
+
+// NewTUIWidgetTreeNode returns new instance of TUIWidgetTreeNode
+func NewTUIWidgetTreeNode(l string, d interface{}) *TUIWidgetTreeNode {
+	n := &TUIWidgetTreeNode{label: l, data: d}
+	return n
+}
+
+// TUIWidgetTree is a widget that shows hierarchical data (eg. filesystem)
+// as a tree. Nodes can be expanded and collapsed with arrow keys and the
+// selected one is highlighted. Typing letters searches for a node with
+// matching label. Only rows that fit in the pane are rendered.
+type TUIWidgetTree struct {
+	root     *TUIWidgetTreeNode
+	showRoot bool
+	selected *TUIWidgetTreeNode
+	offset   int
+	search   string
+	onLoad   func(n *TUIWidgetTreeNode) []*TUIWidgetTreeNode
+	onSelect func(n *TUIWidgetTreeNode)
+}
+
+// GetRoot returns root node
+func (w *TUIWidgetTree) GetRoot() *TUIWidgetTreeNode {
+	return w.root
+}
+
+// GetSelected returns selected node
+func (w *TUIWidgetTree) GetSelected() *TUIWidgetTreeNode {
+	return w.selected
+}
+
+// GetSearch returns currently typed search phrase
+func (w *TUIWidgetTree) GetSearch() string {
+	return w.search
+}
+
+// SetShowRoot sets whether root node should be printed out or only its
+// children
+func (w *TUIWidgetTree) SetShowRoot(b bool) {
+	w.showRoot = b
+}
+
+// SetSelected selects node and expands all its parents so it is visible
+func (w *TUIWidgetTree) SetSelected(n *TUIWidgetTreeNode) {
+	for a := n.parent; a != nil; a = a.parent {
+		w.Expand(a)
+	}
+	w.selected = n
+}
+
+// SetOnLoad sets func that is called when node is expanded for the first
+// time (root is loaded when the tree is drawn). It should return node
+// children.
+func (w *TUIWidgetTree) SetOnLoad(f func(n *TUIWidgetTreeNode) []*TUIWidgetTreeNode) {
+	w.onLoad = f
+}
+
+// SetOnSelect sets func that is called when enter is pressed on a node
+func (w *TUIWidgetTree) SetOnSelect(f func(n *TUIWidgetTreeNode)) {
+	w.onSelect = f
+}
+
+// Expand shows node children, loading them first if necessary
+func (w *TUIWidgetTree) Expand(n *TUIWidgetTreeNode) {
+	w.load(n)
+	n.expanded = true
+}
+
+// load calls onLoad to get node children when they are not loaded yet. Node
+// is not marked as loaded when there is no onLoad, so it can be loaded when
+// it is set later.
+func (w *TUIWidgetTree) load(n *TUIWidgetTreeNode) {
+	if n.loaded || n.leaf || w.onLoad == nil {
+		return
+	}
+	for _, c := range w.onLoad(n) {
+		c.parent = n
+		n.children = append(n.children, c)
+	}
+	n.loaded = true
+}
+
+// hasChildren returns true when node has or may have (not loaded yet)
+// children
+func (w *TUIWidgetTree) hasChildren(n *TUIWidgetTreeNode) bool {
+	if n.leaf {
+		return false
+	}
+	if !n.loaded && w.onLoad != nil {
+		return true
+	}
+	return len(n.children) > 0
+}
+
+// Collapse hides node children
+func (w *TUIWidgetTree) Collapse(n *TUIWidgetTreeNode) {
+	n.expanded = false
+	for s := w.selected; s != nil; s = s.parent {
+		if s.parent == n {
+			w.selected = n
+			break
+		}
+	}
+}
+
+// Toggle expands or collapses node
+func (w *TUIWidgetTree) Toggle(n *TUIWidgetTreeNode) {
+	if n.expanded {
+		w.Collapse(n)
+	} else {
+		w.Expand(n)
+	}
+}
+
+// InitPane sets pane minimal width and height that's necessary for the pane
+// to work.
+func (w *TUIWidgetTree) InitPane(p *TUIPane) {
+	p.SetMinWidth(10)
+	p.SetMinHeight(2)
+}
+
+// Run prints out rows of the tree that fit in the pane
+func (w *TUIWidgetTree) Run(p *TUIPane) int {
+	cw := p.GetContentWidth()
+	ch := p.GetContentHeight()
+	if cw <= 0 || ch <= 0 {
+		return 1
+	}
+	rows := ch
+	if w.search != "" {
+		rows--
+	}
+
+	nodes := w.visibleNodes()
+	sel := w.indexOf(nodes, w.selected)
+	if sel == -1 && len(nodes) > 0 {
+		sel = 0
+		w.selected = nodes[0]
+	}
+	if sel < w.offset {
+		w.offset = sel
+	}
+	if rows > 0 && sel >= w.offset+rows {
+		w.offset = sel - rows + 1
+	}
+	if w.offset > len(nodes)-rows {
+		w.offset = len(nodes) - rows
+	}
+	if w.offset < 0 {
+		w.offset = 0
+	}
+
+	for i := 0; i < rows; i++ {
+		j := w.offset + i
+		if j >= len(nodes) {
+			p.Write(0, i, fitString("", cw), false)
+			continue
+		}
+		line := fitString(w.prefix(nodes[j])+nodes[j].label, cw)
+		if j == sel {
+			line = "\u001b[7m" + line + "\u001b[0m"
+		}
+		p.Write(0, i, line, false)
+	}
+	if w.search != "" {
+		p.Write(0, rows, fitString("/"+w.search, cw), false)
+	}
+	return 1
+}
+
+// KeyPress handles key pressed while the tree is active and redraws it.
+// It returns false when the key was not used by the tree.
+func (w *TUIWidgetTree) KeyPress(p *TUIPane, b []byte) bool {
+	nodes := w.visibleNodes()
+	if len(nodes) == 0 {
+		return false
+	}
+	sel := w.indexOf(nodes, w.selected)
+	if sel == -1 {
+		sel = 0
+	}
+	page := p.GetContentHeight() - 1
+	if page < 1 {
+		page = 1
+	}
+
+	move := func(i int) bool {
+		if i < 0 {
+			i = 0
+		}
+		if i > len(nodes)-1 {
+			i = len(nodes) - 1
+		}
+		w.selected = nodes[i]
+		w.search = ""
+		w.Run(p)
+		return true
+	}
+
+	switch string(b) {
+	case KEY_UP:
+		return move(sel - 1)
+	case KEY_DOWN:
+		return move(sel + 1)
+	case KEY_PGUP:
+		return move(sel - page)
+	case KEY_PGDN:
+		return move(sel + page)
+	case KEY_HOME:
+		return move(0)
+	case KEY_END:
+		return move(len(nodes) - 1)
+	case KEY_RIGHT:
+		n := nodes[sel]
+		if n.expanded && len(n.children) > 0 {
+			return move(sel + 1)
+		}
+		if w.hasChildren(n) {
+			w.Expand(n)
+		}
+	case KEY_LEFT:
+		n := nodes[sel]
+		if !n.expanded && n.parent != nil && (n.parent != w.root || w.showRoot) {
+			return move(w.indexOf(nodes, n.parent))
+		}
+		w.Collapse(n)
+	case KEY_SPACE:
+		w.Toggle(nodes[sel])
+	case KEY_ENTER:
+		if w.onSelect != nil {
+			w.onSelect(nodes[sel])
+		}
+	case KEY_ESC:
+		w.search = ""
+	case KEY_BACKSPACE:
+		if w.search == "" {
+			return false
+		}
+		r := []rune(w.search)
+		w.search = string(r[:len(r)-1])
+		w.find(nodes, sel)
+	default:
+		s := string(b)
+		for _, r := range s {
+			if !unicode.IsPrint(r) {
+				return false
+			}
+		}
+		w.search += s
+		w.find(nodes, sel)
+	}
+	w.Run(p)
+	return true
+}
+
+// find selects first visible node, starting from the selected one, which
+// label contains the search phrase
+func (w *TUIWidgetTree) find(nodes []*TUIWidgetTreeNode, from int) {
+	if w.search == "" {
+		return
+	}
+	q := strings.ToLower(w.search)
+	for i := 0; i < len(nodes); i++ {
+		n := nodes[(from+i)%len(nodes)]
+		if strings.Contains(strings.ToLower(n.label), q) {
+			w.selected = n
+			return
+		}
+	}
+}
+
+// visibleNodes returns flat list of nodes that are not hidden inside
+// collapsed parents
+func (w *TUIWidgetTree) visibleNodes() []*TUIWidgetTreeNode {
+	nodes := []*TUIWidgetTreeNode{}
+	var add func(n *TUIWidgetTreeNode)
+	add = func(n *TUIWidgetTreeNode) {
+		nodes = append(nodes, n)
+		if n.expanded {
+			w.load(n)
+			for _, c := range n.children {
+				add(c)
+			}
+		}
+	}
+	if w.showRoot {
+		add(w.root)
+	} else {
+		w.load(w.root)
+		for _, c := range w.root.children {
+			add(c)
+		}
+	}
+	return nodes
+}
+
+// indexOf returns position of node on the list or -1
+func (w *TUIWidgetTree) indexOf(nodes []*TUIWidgetTreeNode, n *TUIWidgetTreeNode) int {
+	for i, x := range nodes {
+		if x == n {
+			return i
+		}
+	}
+	return -1
+}
+
+// prefix returns indentation guides and expand marker for the node
+func (w *TUIWidgetTree) prefix(n *TUIWidgetTreeNode) string {
+	s := ""
+	if n != w.root {
+		if n.isLast() {
+			s = "└─ "
+		} else {
+			s = "├─ "
+		}
+		for a := n.parent; a != nil && a != w.root; a = a.parent {
+			if a.isLast() {
+				s = "   " + s
+			} else {
+				s = "│  " + s
+			}
+		}
+	}
+	if !w.hasChildren(n) {
+		return s + "  "
+	}
+	if n.expanded {
+		return s + "▾ "
+	}
+	return s + "▸ "
+}
+
+// NewTUIWidgetTree returns instance of TUIWidgetTree struct with the root
+// node, which is expanded. Its children are loaded (see SetOnLoad) when the
+// tree is drawn.
+func NewTUIWidgetTree(root *TUIWidgetTreeNode) *TUIWidgetTree {
+	w := &TUIWidgetTree{root: root}
+	root.expanded = true
+	return w
+}
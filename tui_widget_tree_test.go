@@ -0,0 +1,202 @@
+package terminalui
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// newTestTree returns tree which nodes have n children, loaded when they
+// are expanded, and counts the loads
+func newTestTree(n int, loads *int) *TUIWidgetTree {
+	w := NewTUIWidgetTree(NewTUIWidgetTreeNode("root", nil))
+	w.SetOnLoad(func(p *TUIWidgetTreeNode) []*TUIWidgetTreeNode {
+		*loads++
+		var nodes []*TUIWidgetTreeNode
+		for i := 0; i < n; i++ {
+			nodes = append(nodes, NewTUIWidgetTreeNode(strings.TrimPrefix(p.label+"."+strconv.Itoa(i), "root."), nil))
+		}
+		return nodes
+	})
+	return w
+}
+
+// newTreeTUI returns TUI of specific size that keeps its screen
+func newTreeTUI(w int, h int) *TUI {
+	tui := NewTUI()
+	tui.SetOutput(io.Discard)
+	tui.SetSize(w, h)
+	tui.GetScreen()
+	tui.refreshSize()
+	return tui
+}
+
+// treeLabels returns labels of visible nodes
+func treeLabels(w *TUIWidgetTree) string {
+	var l []string
+	for _, n := range w.visibleNodes() {
+		l = append(l, n.label)
+	}
+	return strings.Join(l, " ")
+}
+
+func TestTreeLazyLoading(t *testing.T) {
+	loads := 0
+	w := newTestTree(2, &loads)
+	if loads != 0 {
+		t.Fatalf("%d loads before tree is shown", loads)
+	}
+	if got := treeLabels(w); got != "0 1" {
+		t.Errorf("got %q", got)
+	}
+	if loads != 1 {
+		t.Errorf("root was loaded %d times", loads)
+	}
+	w.Expand(w.root.children[1])
+	w.Collapse(w.root.children[1])
+	w.Expand(w.root.children[1])
+	if got := treeLabels(w); got != "0 1 1.0 1.1" || loads != 2 {
+		t.Errorf("got %q after %d loads", got, loads)
+	}
+
+	leaf := w.root.children[0]
+	leaf.SetLeaf(true)
+	w.Expand(leaf)
+	if loads != 2 || w.prefix(leaf) != "├─   " {
+		t.Errorf("leaf was loaded or has expand marker %q", w.prefix(leaf))
+	}
+
+	w.root.children[1].ClearChildren()
+	if got := treeLabels(w); got != "0 1 1.0 1.1" || loads != 3 {
+		t.Errorf("got %q after %d loads", got, loads)
+	}
+}
+
+func TestTreeWithoutOnLoad(t *testing.T) {
+	root := NewTUIWidgetTreeNode("root", nil)
+	w := NewTUIWidgetTree(root)
+	n := root.AddChild(NewTUIWidgetTreeNode("a", nil))
+	if w.hasChildren(n) {
+		t.Error("node without children and onLoad has expand marker")
+	}
+	loads := 0
+	w.SetOnLoad(func(p *TUIWidgetTreeNode) []*TUIWidgetTreeNode {
+		loads++
+		return []*TUIWidgetTreeNode{NewTUIWidgetTreeNode("b", nil)}
+	})
+	w.Expand(n)
+	if got := treeLabels(w); got != "a b" || loads != 1 {
+		t.Errorf("got %q after %d loads", got, loads)
+	}
+}
+
+func TestTreeKeys(t *testing.T) {
+	tui := newTreeTUI(20, 5)
+	p := tui.GetPane()
+	loads := 0
+	w := newTestTree(2, &loads)
+	w.Run(p)
+
+	for _, tt := range []struct {
+		key      string
+		selected string
+		visible  string
+	}{
+		{key: KEY_DOWN, selected: "1", visible: "0 1"},
+		{key: KEY_RIGHT, selected: "1", visible: "0 1 1.0 1.1"},
+		{key: KEY_RIGHT, selected: "1.0", visible: "0 1 1.0 1.1"},
+		{key: KEY_SPACE, selected: "1.0", visible: "0 1 1.0 1.0.0 1.0.1 1.1"},
+		{key: KEY_END, selected: "1.1", visible: "0 1 1.0 1.0.0 1.0.1 1.1"},
+		{key: KEY_LEFT, selected: "1", visible: "0 1 1.0 1.0.0 1.0.1 1.1"},
+		{key: KEY_HOME, selected: "0", visible: "0 1 1.0 1.0.0 1.0.1 1.1"},
+		{key: "0.", selected: "1.0.0", visible: "0 1 1.0 1.0.0 1.0.1 1.1"},
+		{key: KEY_ESC, selected: "1.0.0", visible: "0 1 1.0 1.0.0 1.0.1 1.1"},
+	} {
+		if !w.KeyPress(p, []byte(tt.key)) {
+			t.Errorf("%q was not used", tt.key)
+		}
+		if w.selected.label != tt.selected || treeLabels(w) != tt.visible {
+			t.Errorf("%q: selected %q with %q visible", tt.key, w.selected.label, treeLabels(w))
+		}
+	}
+	if w.GetSearch() != "" {
+		t.Errorf("search %q was not cleared", w.GetSearch())
+	}
+	if w.KeyPress(p, []byte("\u0001")) {
+		t.Error("control key was used")
+	}
+}
+
+func TestTreeCollapseSelectsParent(t *testing.T) {
+	loads := 0
+	w := newTestTree(2, &loads)
+	n := NewTUIWidgetTreeNode("x", nil)
+	w.visibleNodes()
+	w.Expand(w.root.children[0])
+	w.Expand(w.root.children[0].children[1])
+	deep := w.root.children[0].children[1].children[0]
+	w.SetSelected(deep)
+	w.Collapse(w.root.children[0])
+	if w.selected != w.root.children[0] {
+		t.Errorf("selected %q after collapse", w.selected.label)
+	}
+	w.SetSelected(n)
+	w.Collapse(w.root.children[1])
+	if w.selected != n {
+		t.Errorf("selection changed when other node was collapsed")
+	}
+}
+
+func TestTreeSearch(t *testing.T) {
+	tui := newTreeTUI(20, 5)
+	p := tui.GetPane()
+	loads := 0
+	w := newTestTree(3, &loads)
+	w.Expand(w.root)
+	w.Run(p)
+	w.KeyPress(p, []byte("2"))
+	if w.selected.label != "2" {
+		t.Errorf("selected %q", w.selected.label)
+	}
+	if got := tui.GetScreen().GetLine(4); got != "/2" {
+		t.Errorf("search line is %q", got)
+	}
+	w.KeyPress(p, []byte(KEY_BACKSPACE))
+	if w.GetSearch() != "" || w.selected.label != "2" {
+		t.Errorf("search %q, selected %q", w.GetSearch(), w.selected.label)
+	}
+	if w.KeyPress(p, []byte(KEY_BACKSPACE)) {
+		t.Error("backspace was used with empty search")
+	}
+}
+
+func TestTreeRowsVirtualization(t *testing.T) {
+	tui := newTreeTUI(20, 3)
+	p := tui.GetPane()
+	loads := 0
+	w := newTestTree(100, &loads)
+	w.Run(p)
+	lines := func() string {
+		s := tui.GetScreen()
+		return s.GetLine(0) + "|" + s.GetLine(1) + "|" + s.GetLine(2)
+	}
+	if got := lines(); got != "├─ ▸ 0|├─ ▸ 1|├─ ▸ 2" {
+		t.Errorf("got %q", got)
+	}
+	w.KeyPress(p, []byte(KEY_END))
+	if w.offset != 97 {
+		t.Errorf("offset is %d, want 97", w.offset)
+	}
+	if got := lines(); got != "├─ ▸ 97|├─ ▸ 98|└─ ▸ 99" {
+		t.Errorf("got %q", got)
+	}
+	w.KeyPress(p, []byte(KEY_PGUP))
+	if w.selected.label != "97" || w.offset != 97 {
+		t.Errorf("selected %q with offset %d", w.selected.label, w.offset)
+	}
+	w.KeyPress(p, []byte(KEY_UP))
+	if w.offset != 96 {
+		t.Errorf("offset is %d, want 96", w.offset)
+	}
+}
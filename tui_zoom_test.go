@@ -0,0 +1,100 @@
+package terminalui
+
+import (
+	"testing"
+)
+
+// paneArea returns position and size of the pane
+func paneArea(p *TUIPane) [4]int {
+	return [4]int{p.GetLeft(), p.GetTop(), p.GetWidth(), p.GetHeight()}
+}
+
+func TestZoom(t *testing.T) {
+	tui := newTreeTUI(40, 10)
+	a, b := tui.GetPane().SplitVertically(-15, UNIT_CHAR)
+	c, d := b.SplitHorizontally(50, UNIT_PERCENT)
+	tui.layout()
+	panes := []*TUIPane{a, b, c, d}
+	want := map[*TUIPane][4]int{}
+	for _, p := range panes {
+		want[p] = paneArea(p)
+	}
+	tui.SetFocus(a)
+
+	tui.ToggleZoom(c)
+	tui.layout()
+	if tui.GetZoomed() != c {
+		t.Fatalf("zoomed pane is %v", tui.GetZoomed())
+	}
+	if got := paneArea(c); got != [4]int{0, 0, 40, 10} {
+		t.Errorf("zoomed pane area is %v", got)
+	}
+	if tui.GetFocus() != a {
+		t.Error("focus changed when zooming")
+	}
+	if tui.GetPaneAt(1, 1) != c {
+		t.Errorf("pane at 1,1 is %v", tui.GetPaneAt(1, 1))
+	}
+	tui.mouseEvent(&TUIMouseEvent{Button: MOUSE_LEFT, X: 1, Y: 1, Pressed: true})
+	if tui.GetFocus() != c {
+		t.Error("click on zoomed pane did not focus it")
+	}
+
+	o := NewTUIOverlay("dialog", tui, 40, 10)
+	o.Open()
+	tui.layout()
+	if tui.GetPaneAt(1, 1) != o.GetPane() {
+		t.Errorf("pane at 1,1 is %v, want overlay", tui.GetPaneAt(1, 1))
+	}
+	if !c.covered {
+		t.Error("zoomed pane is not covered by overlay")
+	}
+	o.Close()
+
+	tui.ToggleZoom(c)
+	tui.layout()
+	if tui.GetZoomed() != nil {
+		t.Fatalf("zoomed pane is %v after toggling", tui.GetZoomed())
+	}
+	for i, p := range panes {
+		if got := paneArea(p); got != want[p] {
+			t.Errorf("pane %d area is %v, want %v", i, got, want[p])
+		}
+	}
+	if c.covered {
+		t.Error("pane is covered after overlay was closed")
+	}
+	if tui.GetFocus() != c {
+		t.Error("focus changed when unzooming")
+	}
+
+	tui.ToggleZoom(tui.GetPane())
+	if tui.GetZoomed() != nil {
+		t.Error("main pane got zoomed")
+	}
+}
+
+func TestToggleZoomWhileRunning(t *testing.T) {
+	tui := NewTUI()
+	tui.SetSize(40, 10)
+	a, _ := tui.GetPane().SplitVertically(50, UNIT_PERCENT)
+	runTUI(t, tui)
+
+	for _, want := range []*TUIPane{a, nil, a} {
+		tui.ToggleZoom(a)
+		var got *TUIPane
+		var w int
+		// second function is called after the interface is laid out again
+		tui.PostAndWait(func() {})
+		tui.PostAndWait(func() {
+			got = tui.GetZoomed()
+			w = a.GetWidth()
+		})
+		if got != want {
+			t.Errorf("zoomed pane is %v, want %v", got, want)
+		}
+		if want != nil && w != 40 {
+			t.Errorf("zoomed pane width is %d", w)
+		}
+	}
+}
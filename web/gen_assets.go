@@ -0,0 +1,127 @@
+//go:build ignore
+
+// This program downloads xterm.js files that are embedded in the web
+// package. Packages are pinned to specific versions and their tarballs are
+// checked against integrity hashes from npm registry. Run it with:
+//
+//	go generate ./web
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// asset is a file from npm package that is saved in assets directory
+type asset struct {
+	pkg     string
+	version string
+	files   map[string]string
+}
+
+var assets = []asset{
+	{"@xterm/xterm", "5.5.0", map[string]string{
+		"package/lib/xterm.js":  "xterm.js",
+		"package/css/xterm.css": "xterm.css",
+		"package/LICENSE":       "LICENSE.xterm",
+	}},
+	{"@xterm/addon-fit", "0.10.0", map[string]string{
+		"package/lib/addon-fit.js": "addon-fit.js",
+		"package/LICENSE":          "LICENSE.addon-fit",
+	}},
+}
+
+// npmVersion is the part of npm registry response that is needed
+type npmVersion struct {
+	Dist struct {
+		Tarball   string `json:"tarball"`
+		Integrity string `json:"integrity"`
+	} `json:"dist"`
+}
+
+func main() {
+	for _, a := range assets {
+		err := download(a)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, a.pkg+"@"+a.version+": "+err.Error())
+			os.Exit(1)
+		}
+	}
+}
+
+// download gets package tarball, checks its hash and saves the files
+func download(a asset) error {
+	b, err := get("https://registry.npmjs.org/" + a.pkg + "/" + a.version)
+	if err != nil {
+		return err
+	}
+	v := &npmVersion{}
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return err
+	}
+	tgz, err := get(v.Dist.Tarball)
+	if err != nil {
+		return err
+	}
+	sum := sha512.Sum512(tgz)
+	if "sha512-"+base64.StdEncoding.EncodeToString(sum[:]) != v.Dist.Integrity {
+		return errors.New("tarball does not match integrity " + v.Dist.Integrity)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(tgz))
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	found := 0
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		n, ok := a.files[h.Name]
+		if !ok {
+			continue
+		}
+		f, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(filepath.Join("assets", n), f, 0644)
+		if err != nil {
+			return err
+		}
+		found++
+	}
+	if found != len(a.files) {
+		return errors.New("package is missing some of the files")
+	}
+	return nil
+}
+
+// get returns body of the response
+func get(u string) ([]byte, error) {
+	r, err := http.Get(u)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		return nil, errors.New(u + ": " + r.Status)
+	}
+	return io.ReadAll(r.Body)
+}
@@ -0,0 +1,144 @@
+// Package web publishes terminal interfaces made with terminalui to
+// browsers. Handler serves a page with xterm.js terminal which connects back
+// over WebSocket. Each connection gets its own TUI which output is streamed
+// to the browser, and key presses and terminal size changes are sent back.
+// Files of xterm.js are embedded in the package (see assets directory) so
+// nothing is loaded from other servers.
+package web
+
+import (
+	"embed"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	terminalui "github.com/go-phings/terminal-ui"
+	"github.com/gorilla/websocket"
+)
+
+//go:generate go run gen_assets.go
+
+//go:embed index.html
+var page []byte
+
+//go:embed assets
+var assets embed.FS
+
+// Handler is an http.Handler that serves the terminal page and, on the same
+// path, WebSocket connections
+type Handler struct {
+	newTUI   func(*http.Request) *terminalui.TUI
+	upgrader websocket.Upgrader
+}
+
+// message is what browser sends: key presses as "input" and terminal size
+// as "resize"
+type message struct {
+	Type string `json:"type"`
+	Data string `json:"data"`
+	Cols int    `json:"cols"`
+	Rows int    `json:"rows"`
+}
+
+// ServeHTTP serves the page and xterm.js files (requested with "asset"
+// query parameter on the same path) or upgrades request to WebSocket
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if a := r.URL.Query().Get("asset"); a != "" {
+		serveAsset(w, r, a)
+		return
+	}
+	if !websocket.IsWebSocketUpgrade(r) {
+		if _, err := fs.Stat(assets, "assets/xterm.js"); err != nil {
+			http.Error(w, "xterm.js is missing, run: go generate ./web", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page)
+		return
+	}
+	c, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer c.Close()
+
+	t := h.newTUI(r)
+	in, inw := io.Pipe()
+	t.SetInput(in)
+	t.SetOutput(&wsWriter{conn: c})
+	go h.readMessages(c, t, inw)
+	t.Run(nil, nil)
+	in.Close()
+	c.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+}
+
+// readMessages passes key presses and size changes from the browser to TUI.
+// When connection is closed, TUI input ends so it stops.
+func (h *Handler) readMessages(c *websocket.Conn, t *terminalui.TUI, w *io.PipeWriter) {
+	defer w.Close()
+	for {
+		m := &message{}
+		err := c.ReadJSON(m)
+		if err != nil {
+			if _, ok := err.(*json.SyntaxError); ok {
+				continue
+			}
+			return
+		}
+		switch m.Type {
+		case "input":
+			// xterm.js sends carriage return for enter key
+			_, err = io.WriteString(w, strings.ReplaceAll(m.Data, "\r", "\n"))
+			if err != nil {
+				return
+			}
+		case "resize":
+			if m.Cols > 0 && m.Rows > 0 {
+				t.SetSize(m.Cols, m.Rows)
+			}
+		}
+	}
+}
+
+// serveAsset writes embedded xterm.js file
+func serveAsset(w http.ResponseWriter, r *http.Request, n string) {
+	b, err := assets.ReadFile("assets/" + path.Base(n))
+	if err != nil || path.Ext(n) == ".md" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(n)))
+	w.Header().Set("Cache-Control", "max-age=86400")
+	w.Write(b)
+}
+
+// wsWriter sends everything written to it as binary WebSocket messages
+type wsWriter struct {
+	conn *websocket.Conn
+	mu   sync.Mutex
+}
+
+// Write sends bytes to the browser
+func (w *wsWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	err := w.conn.WriteMessage(websocket.BinaryMessage, b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// NewHandler returns new instance of Handler. Function f is called for every
+// new connection and has to return a new TUI (with panes, widgets etc.) that
+// is not running.
+func NewHandler(f func(*http.Request) *terminalui.TUI) *Handler {
+	h := &Handler{newTUI: f}
+	return h
+}
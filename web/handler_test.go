@@ -0,0 +1,46 @@
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	terminalui "github.com/go-phings/terminal-ui"
+)
+
+func TestHandlerAssets(t *testing.T) {
+	h := NewHandler(func(r *http.Request) *terminalui.TUI {
+		return terminalui.NewTUI()
+	})
+	for _, tt := range []struct {
+		url  string
+		code int
+	}{
+		{"/?asset=README.md", http.StatusNotFound},
+		{"/?asset=../handler.go", http.StatusNotFound},
+		{"/?asset=missing.js", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s: got %d, want %d", tt.url, w.Code, tt.code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if _, err := assets.ReadFile("assets/xterm.js"); err != nil {
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("page without xterm.js: got %d, want %d", w.Code, http.StatusInternalServerError)
+		}
+		return
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("page: got %d", w.Code)
+	}
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/?asset=xterm.js", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/javascript; charset=utf-8" {
+		t.Errorf("xterm.js: got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+}